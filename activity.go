@@ -0,0 +1,71 @@
+package smhi
+
+import (
+	"math"
+	"time"
+)
+
+// ActivityRule describes the conditions suitable for an activity. All
+// limits apply so set each field, e.g. use math.Inf(1) for MaxTemperature
+// when there is no upper limit.
+type ActivityRule struct {
+	Name           string
+	MinTemperature float64
+	MaxTemperature float64
+	// MaxWindSpeed is in m/s.
+	MaxWindSpeed float64
+	// MaxRain is the highest acceptable precipitation, see
+	// TimeSeriesItem.RainClass. The zero value RainNone means no
+	// precipitation at all.
+	MaxRain RainClass
+}
+
+// Preset activity rules.
+var (
+	ActivityCycling = ActivityRule{Name: "Cycling", MinTemperature: 5, MaxTemperature: 30, MaxWindSpeed: 8, MaxRain: RainNone}
+	ActivityRunning = ActivityRule{Name: "Running", MinTemperature: -10, MaxTemperature: 25, MaxWindSpeed: 10, MaxRain: RainLight}
+	ActivityPicnic  = ActivityRule{Name: "Picnic", MinTemperature: 15, MaxTemperature: 30, MaxWindSpeed: 6, MaxRain: RainNone}
+)
+
+// Suitable returns whether the conditions of this forecast timeseries item
+// are within the limits of the rule.
+func (i TimeSeriesItem) Suitable(rule ActivityRule) bool {
+	t := i.Temperature()
+	return t >= rule.MinTemperature && t <= rule.MaxTemperature &&
+		i.WindSpeed() <= rule.MaxWindSpeed &&
+		i.RainClass() <= rule.MaxRain
+}
+
+// SuitableWindows returns the windows where every item is suitable for the
+// rule and that last at least minDuration.
+func (f *Forecast) SuitableWindows(rule ActivityRule, minDuration time.Duration) []TimeWindow {
+	var windows []TimeWindow
+	for _, run := range f.runs(func(i TimeSeriesItem) bool { return i.Suitable(rule) }) {
+		if w := f.runWindow(run[0], run[1]); w.Duration() >= minDuration {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
+
+// ComfortableHours returns the number of hours per calendar day in loc that
+// are suitable for the rule, keyed by date formatted as 2006-01-02. Each
+// suitable item counts the time until the next item, attributed to the day
+// of the item like Daily, and the total is rounded to whole hours.
+func (f *Forecast) ComfortableHours(loc *time.Location, rule ActivityRule) map[string]int {
+	hours := make(map[string]float64)
+	for idx, item := range f.TimeSeries {
+		var h float64
+		if item.Suitable(rule) {
+			h = f.stepDuration(idx).Hours()
+		}
+		// Days without suitable hours are included with 0.
+		hours[item.ValidTime.In(loc).Format(time.DateOnly)] += h
+	}
+
+	counts := make(map[string]int, len(hours))
+	for day, h := range hours {
+		counts[day] = int(math.Round(h))
+	}
+	return counts
+}
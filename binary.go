@@ -0,0 +1,43 @@
+package smhi
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+)
+
+// binaryVersion is the first byte of the MarshalBinary format.
+const binaryVersion = 1
+
+// gobForecast has the fields but not the methods of Forecast so gob doesn't
+// call MarshalBinary recursively.
+type gobForecast Forecast
+
+// MarshalBinary implements encoding.BinaryMarshaler. The format is a version
+// byte followed by the gob encoded forecast. It is smaller and faster to
+// decode than SMHI's JSON, e.g. for caching.
+func (f *Forecast) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(binaryVersion)
+	if err := gob.NewEncoder(&buf).Encode((*gobForecast)(f)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (f *Forecast) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("empty binary forecast")
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("unsupported binary forecast version %d", data[0])
+	}
+	var decoded gobForecast
+	if err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(&decoded); err != nil {
+		return err
+	}
+	*f = Forecast(decoded)
+	return nil
+}
@@ -0,0 +1,417 @@
+package smhi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// DefaultBaseURL is the base URL of the SMHI meteorological forecasts API.
+const DefaultBaseURL = "https://opendata-download-metfcst.smhi.se"
+
+// ErrPointNotCovered is returned when SMHI responds with 404, which it does
+// for coordinates outside of the forecast model domain. The error also wraps
+// the *APIError.
+var ErrPointNotCovered = errors.New("point not covered by forecast")
+
+// ErrTimeout is returned when a request times out, either because the
+// context deadline was exceeded or the HTTP client timed out.
+var ErrTimeout = errors.New("request timed out")
+
+// wrapTimeout wraps timeout errors with ErrTimeout.
+func wrapTimeout(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
+
+// ErrInvalidCoordinate is returned for coordinates that can't be requested.
+var ErrInvalidCoordinate = errors.New("invalid coordinate")
+
+// validateCoordinate rejects coordinates outside of valid ranges and the
+// exact 0,0 coordinate, which is almost always an unset value rather than a
+// request for the Gulf of Guinea.
+func validateCoordinate(lon, lat float64) error {
+	if lon == 0 && lat == 0 {
+		return fmt.Errorf("%w: longitude and latitude are both 0, set them to a location in the forecast area", ErrInvalidCoordinate)
+	}
+	if !(lon >= -180 && lon <= 180 && lat >= -90 && lat <= 90) {
+		return fmt.Errorf("%w: %f,%f is out of range", ErrInvalidCoordinate, lon, lat)
+	}
+	return nil
+}
+
+// APIError is returned when SMHI responds with a status other than 200 OK.
+type APIError struct {
+	StatusCode int
+	Body       []byte
+	// RetryAfter is how long SMHI asked the client to wait before the next
+	// request, from the Retry-After header of e.g. a 429 Too Many Requests
+	// response. Zero if the header is missing.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("status %d is not ok: %s", e.StatusCode, e.Body)
+}
+
+// DefaultTimeout is the request timeout of DefaultHTTPClient. SMHI normally
+// responds within a second so this only guards against hanging forever.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultRetryDelay is how long Client waits before retrying a 429 Too Many
+// Requests response without a Retry-After header.
+const DefaultRetryDelay = time.Second
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date. Returns zero if the header is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(0, time.Duration(seconds)*time.Second)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(0, t.Sub(now))
+	}
+	return 0
+}
+
+// DefaultHTTPClient is shared by all clients without an HTTPClient. Its
+// transport keeps more idle connections per host than http.DefaultTransport
+// so that fetching many locations reuses connections to SMHI. Requests time
+// out after DefaultTimeout, including reading the response body, unless the
+// context passed to the request has an earlier deadline.
+var DefaultHTTPClient = &http.Client{
+	Transport: newTransport(),
+	Timeout:   DefaultTimeout,
+}
+
+func newTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ForceAttemptHTTP2 = true
+	t.MaxIdleConns = 100
+	t.MaxIdleConnsPerHost = 32
+	t.IdleConnTimeout = 90 * time.Second
+	return t
+}
+
+// Client requests forecasts from SMHI. The zero value is ready to use.
+type Client struct {
+	// HTTPClient is used to make requests. If nil, DefaultHTTPClient is
+	// used, which times out after DefaultTimeout. Every request made by the
+	// client, including by GetGrid, GetDailyForecast, GetForecastByName,
+	// GetLatestRadar and GetHydroWarnings, goes through HTTPClient so a custom Transport, e.g.
+	// with proxy authentication or client certificates, is always honored.
+	HTTPClient *http.Client
+
+	// BaseURL overrides DefaultBaseURL, e.g. for testing.
+	BaseURL string
+
+	// RadarBaseURL overrides DefaultRadarBaseURL, e.g. for testing.
+	RadarBaseURL string
+
+	// WarningsBaseURL overrides DefaultWarningsBaseURL, e.g. for testing.
+	WarningsBaseURL string
+
+	// RecordDir, if set, is a directory where each raw response is written
+	// to a file keyed by coordinate.
+	RecordDir string
+
+	// ReplayDir, if set, is a directory with responses previously written
+	// by RecordDir. Responses are read from there instead of the network.
+	ReplayDir string
+
+	// Logger, if set, is called after each HTTP request with the URL, the
+	// response status (0 if the request failed) and how long it took.
+	Logger func(url string, status int, duration time.Duration)
+
+	// Progress, if set, is called while the response body is read with
+	// the number of bytes read so far and the Content-Length (-1 if
+	// unknown).
+	Progress func(read, total int64)
+
+	// GridWorkers is the number of concurrent requests made by GetGrid. If
+	// zero, DefaultGridWorkers is used.
+	GridWorkers int
+
+	// MaxRetries is the number of times any request is retried when SMHI
+	// responds with 429 Too Many Requests. The client waits as long as the
+	// Retry-After header says, or DefaultRetryDelay without the header, and
+	// gives up early if the context is done. If zero, the *APIError is
+	// returned and its RetryAfter tells when to try again.
+	MaxRetries int
+
+	// Parameters, if set, are the names of the parameters to keep, e.g.
+	// ParamTemperature. Other parameters are removed from decoded forecasts
+	// to save memory. SMHI's point API has no parameter selection so the
+	// pruning is done client-side and the full response is still
+	// downloaded (and recorded to RecordDir).
+	Parameters []string
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return DefaultHTTPClient
+}
+
+func (c *Client) forecastURL(lon, lat float64) string {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	return fmt.Sprintf("%s/api/category/pmp3g/version/2/geotype/point/lon/%f/lat/%f/data.json", base, lon, lat)
+}
+
+// fixtureName returns the file name used by RecordDir and ReplayDir.
+func fixtureName(lon, lat float64) string {
+	return fmt.Sprintf("%f_%f.json", lon, lat)
+}
+
+// ResponseMeta describes the HTTP response a forecast was decoded from.
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+	// ContentLength is the length of the response body as reported by
+	// SMHI, or -1 if unknown.
+	ContentLength int64
+}
+
+// progressReader reports the number of bytes read so far.
+type progressReader struct {
+	r        io.Reader
+	read     int64
+	total    int64
+	progress func(read, total int64)
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	if n > 0 {
+		p.read += int64(n)
+		p.progress(p.read, p.total)
+	}
+	return n, err
+}
+
+// readResponse reads and closes the response body. Responses with a status
+// other than 200 OK are returned as *APIError.
+func readResponse(resp *http.Response, progress func(read, total int64)) ([]byte, error) {
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	if progress != nil {
+		body = &progressReader{r: resp.Body, total: resp.ContentLength, progress: progress}
+	}
+
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{
+			StatusCode: resp.StatusCode,
+			Body:       buf,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %w", ErrPointNotCovered, apiErr)
+		}
+		return nil, apiErr
+	}
+
+	return buf, nil
+}
+
+// ParseForecastResponse decodes a forecast from a response to a SMHI forecast
+// request, e.g. in a proxy. It checks the status like Client.GetForecast and
+// reads and closes the body.
+func ParseForecastResponse(resp *http.Response) (*Forecast, error) {
+	buf, err := readResponse(resp, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseForecast(buf)
+}
+
+// GetForecast requests the 10 day forecast for a longitude/latitude coordinate.
+func (c *Client) GetForecast(ctx context.Context, lon, lat float64) (*Forecast, error) {
+	forecast, _, err := c.GetForecastWithMeta(ctx, lon, lat)
+	return forecast, err
+}
+
+// GetForecastWithMeta is like GetForecast but also returns metadata about the
+// HTTP response. When replaying from ReplayDir the metadata describes the
+// file.
+func (c *Client) GetForecastWithMeta(ctx context.Context, lon, lat float64) (*Forecast, *ResponseMeta, error) {
+	if err := validateCoordinate(lon, lat); err != nil {
+		return nil, nil, err
+	}
+
+	buf, meta, err := c.fetch(ctx, lon, lat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	forecast, err := ParseForecast(buf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(c.Parameters) > 0 {
+		forecast.prune(c.Parameters)
+	}
+
+	return forecast, meta, nil
+}
+
+// GetForecastInto is like GetForecast but decodes into dst, see
+// ParseForecastInto. It is intended for pollers refreshing many forecasts
+// on a schedule. If the request fails dst is left untouched, but if the
+// response can't be decoded dst is partly overwritten.
+func (c *Client) GetForecastInto(ctx context.Context, lon, lat float64, dst *Forecast) error {
+	if err := validateCoordinate(lon, lat); err != nil {
+		return err
+	}
+
+	buf, _, err := c.fetch(ctx, lon, lat)
+	if err != nil {
+		return err
+	}
+
+	if err := ParseForecastInto(buf, dst); err != nil {
+		return err
+	}
+
+	if len(c.Parameters) > 0 {
+		dst.prune(c.Parameters)
+	}
+
+	return nil
+}
+
+// GetForecastRaw requests the forecast for a longitude/latitude coordinate
+// like GetForecast but returns the unparsed response body, e.g. for
+// debugging.
+func (c *Client) GetForecastRaw(ctx context.Context, lon, lat float64) ([]byte, error) {
+	if err := validateCoordinate(lon, lat); err != nil {
+		return nil, err
+	}
+
+	buf, _, err := c.fetch(ctx, lon, lat)
+	return buf, err
+}
+
+// prune removes all parameters except the named ones.
+func (f *Forecast) prune(names []string) {
+	for idx := range f.TimeSeries {
+		item := &f.TimeSeries[idx]
+		item.Parameters = slices.DeleteFunc(item.Parameters, func(p Parameter) bool {
+			return !slices.Contains(names, p.Name)
+		})
+	}
+}
+
+// do makes a GET request and reads the response. The response is returned
+// if there was one, even on error.
+func (c *Client) do(ctx context.Context, url string) ([]byte, *http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient().Do(req)
+	if c.Logger != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		c.Logger(url, status, time.Since(start))
+	}
+	if err != nil {
+		return nil, nil, wrapTimeout(err)
+	}
+
+	buf, err := readResponse(resp, c.Progress)
+	if err != nil {
+		return nil, resp, wrapTimeout(err)
+	}
+
+	return buf, resp, nil
+}
+
+// get makes a GET request with do and retries it up to MaxRetries times
+// while SMHI responds with 429 Too Many Requests.
+func (c *Client) get(ctx context.Context, url string) ([]byte, *http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		buf, resp, err := c.do(ctx, url)
+		var apiErr *APIError
+		if attempt >= c.MaxRetries || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+			return buf, resp, err
+		}
+		delay := DefaultRetryDelay
+		if resp.Header.Get("Retry-After") != "" {
+			delay = apiErr.RetryAfter
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, nil, wrapTimeout(err)
+		}
+	}
+}
+
+// sleep waits for the duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) fetch(ctx context.Context, lon, lat float64) ([]byte, *ResponseMeta, error) {
+	if c.ReplayDir != "" {
+		buf, err := os.ReadFile(filepath.Join(c.ReplayDir, fixtureName(lon, lat)))
+		if err != nil {
+			return nil, nil, err
+		}
+		return buf, &ResponseMeta{StatusCode: http.StatusOK, Header: http.Header{}, ContentLength: int64(len(buf))}, nil
+	}
+
+	buf, resp, err := c.get(ctx, c.forecastURL(lon, lat))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if c.RecordDir != "" {
+		if err := os.WriteFile(filepath.Join(c.RecordDir, fixtureName(lon, lat)), buf, 0o644); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	meta := &ResponseMeta{
+		StatusCode:    resp.StatusCode,
+		Header:        resp.Header,
+		ContentLength: resp.ContentLength,
+	}
+
+	return buf, meta, nil
+}
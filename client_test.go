@@ -0,0 +1,524 @@
+package smhi_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tomyl/smhi"
+)
+
+func newTestServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func serveTestdata(t *testing.T) http.HandlerFunc {
+	t.Helper()
+	buf, err := os.ReadFile("testdata/data.json")
+	require.Nil(t, err)
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(buf)))
+		w.Write(buf)
+	}
+}
+
+func TestClientRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	server := newTestServer(t, serveTestdata(t))
+
+	client := smhi.Client{BaseURL: server.URL, RecordDir: dir}
+	recorded, err := client.GetForecast(context.Background(), 18.040468, 59.340379)
+	require.Nil(t, err)
+
+	server.Close()
+
+	client = smhi.Client{BaseURL: server.URL, ReplayDir: dir}
+	replayed, err := client.GetForecast(context.Background(), 18.040468, 59.340379)
+	require.Nil(t, err)
+	require.Equal(t, recorded, replayed)
+
+	_, err = client.GetForecast(context.Background(), 11.97, 57.71)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestClientParameters(t *testing.T) {
+	server := newTestServer(t, serveTestdata(t))
+
+	client := smhi.Client{BaseURL: server.URL, Parameters: []string{smhi.ParamTemperature, smhi.ParamWeatherSymbol}}
+	forecast, err := client.GetForecast(context.Background(), 18.040468, 59.340379)
+	require.Nil(t, err)
+	require.Len(t, forecast.TimeSeries, 74)
+	for _, item := range forecast.TimeSeries {
+		require.Len(t, item.Parameters, 2)
+	}
+	require.Equal(t, 20.6, forecast.TimeSeries[0].Temperature())
+}
+
+func TestGetForecastInto(t *testing.T) {
+	server := newTestServer(t, serveTestdata(t))
+
+	client := smhi.Client{BaseURL: server.URL}
+	var forecast smhi.Forecast
+	for range 2 {
+		require.Nil(t, client.GetForecastInto(context.Background(), 18.040468, 59.340379, &forecast))
+		require.Len(t, forecast.TimeSeries, 74)
+	}
+
+	client.Parameters = []string{smhi.ParamTemperature}
+	require.Nil(t, client.GetForecastInto(context.Background(), 18.040468, 59.340379, &forecast))
+	require.Len(t, forecast.TimeSeries[0].Parameters, 1)
+}
+
+func TestGetForecastRaw(t *testing.T) {
+	server := newTestServer(t, serveTestdata(t))
+
+	client := smhi.Client{BaseURL: server.URL}
+	buf, err := client.GetForecastRaw(context.Background(), 18.040468, 59.340379)
+	require.Nil(t, err)
+	expected, err := os.ReadFile("testdata/data.json")
+	require.Nil(t, err)
+	require.Equal(t, expected, buf)
+}
+
+func TestGetForecastByName(t *testing.T) {
+	var path string
+	handler := serveTestdata(t)
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		handler(w, r)
+	})
+
+	client := smhi.Client{BaseURL: server.URL}
+	_, err := client.GetForecastByName(context.Background(), nil, "Göteborg")
+	require.Nil(t, err)
+	require.Contains(t, path, "/lon/11.974600/lat/57.708900/")
+
+	_, err = client.GetForecastByName(context.Background(), nil, "Atlantis")
+	require.ErrorIs(t, err, smhi.ErrUnknownPlace)
+}
+
+func TestPointNotCovered(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"code":404,"message":"Requested point is out of bounds"}`, http.StatusNotFound)
+	})
+
+	client := smhi.Client{BaseURL: server.URL}
+	_, err := client.GetForecast(context.Background(), -30, 10)
+	require.ErrorIs(t, err, smhi.ErrPointNotCovered)
+
+	var apiErr *smhi.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	require.Contains(t, string(apiErr.Body), "out of bounds")
+}
+
+func TestAPIError(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oops", http.StatusInternalServerError)
+	})
+
+	client := smhi.Client{BaseURL: server.URL}
+	_, err := client.GetForecast(context.Background(), 18.040468, 59.340379)
+	require.NotErrorIs(t, err, smhi.ErrPointNotCovered)
+
+	var apiErr *smhi.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+}
+
+func TestInvalidCoordinate(t *testing.T) {
+	var client smhi.Client
+	_, err := client.GetForecast(context.Background(), 0, 0)
+	require.ErrorIs(t, err, smhi.ErrInvalidCoordinate)
+
+	_, err = client.GetForecast(context.Background(), 59.3, 180.5)
+	require.ErrorIs(t, err, smhi.ErrInvalidCoordinate)
+}
+
+func TestClientLogger(t *testing.T) {
+	server := newTestServer(t, serveTestdata(t))
+
+	var urls []string
+	var statuses []int
+	client := smhi.Client{
+		BaseURL: server.URL,
+		Logger: func(url string, status int, duration time.Duration) {
+			urls = append(urls, url)
+			statuses = append(statuses, status)
+		},
+	}
+	_, err := client.GetForecast(context.Background(), 18.040468, 59.340379)
+	require.Nil(t, err)
+	require.Equal(t, []string{server.URL + "/api/category/pmp3g/version/2/geotype/point/lon/18.040468/lat/59.340379/data.json"}, urls)
+	require.Equal(t, []int{http.StatusOK}, statuses)
+}
+
+func TestGetForecastWithMeta(t *testing.T) {
+	server := newTestServer(t, serveTestdata(t))
+
+	var read, total int64
+	client := smhi.Client{
+		BaseURL: server.URL,
+		Progress: func(r, t int64) {
+			read, total = r, t
+		},
+	}
+	forecast, meta, err := client.GetForecastWithMeta(context.Background(), 18.040468, 59.340379)
+	require.Nil(t, err)
+	require.NotNil(t, forecast)
+
+	info, err := os.Stat("testdata/data.json")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, meta.StatusCode)
+	require.Equal(t, info.Size(), meta.ContentLength)
+	require.Equal(t, info.Size(), read)
+	require.Equal(t, info.Size(), total)
+}
+
+func TestGetGrid(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	handler := serveTestdata(t)
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		handler(w, r)
+	})
+
+	client := smhi.Client{BaseURL: server.URL, GridWorkers: 2}
+	forecasts, err := client.GetGrid(context.Background(), 18.0, 59.0, 18.2, 59.1, 0.1)
+	require.Nil(t, err)
+	require.Len(t, forecasts, 6)
+	require.Len(t, paths, 6)
+	require.Contains(t, forecasts, smhi.Point{18.2, 59.1})
+
+	_, err = client.GetGrid(context.Background(), 18.0, 59.0, 18.2, 59.1, 0)
+	require.NotNil(t, err)
+	_, err = client.GetGrid(context.Background(), 18.0, 59.0, 18.2, 59.1, math.NaN())
+	require.NotNil(t, err)
+	_, err = client.GetGrid(context.Background(), math.NaN(), 59.0, 18.2, 59.1, 0.1)
+	require.ErrorIs(t, err, smhi.ErrInvalidCoordinate)
+	_, err = client.GetGrid(context.Background(), 18.0, 59.0, 18.2, 91, 0.1)
+	require.ErrorIs(t, err, smhi.ErrInvalidCoordinate)
+	_, err = client.GetGrid(context.Background(), 10.0, 55.0, 24.0, 69.0, 1e-6)
+	require.ErrorContains(t, err, "points")
+	require.Len(t, paths, 6)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestCustomTransport(t *testing.T) {
+	rec := httptest.NewRecorder()
+	serveTestdata(t)(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rec.Body.Bytes()
+
+	var mu sync.Mutex
+	var calls int
+	client := smhi.Client{
+		// The host doesn't resolve so any request bypassing the transport
+		// fails.
+		BaseURL: "http://smhi.invalid",
+		HTTPClient: &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+				return &http.Response{
+					StatusCode:    http.StatusOK,
+					Header:        http.Header{},
+					Body:          io.NopCloser(bytes.NewReader(body)),
+					ContentLength: int64(len(body)),
+					Request:       req,
+				}, nil
+			}),
+		},
+	}
+
+	ctx := context.Background()
+	_, err := client.GetForecast(ctx, 18.040468, 59.340379)
+	require.Nil(t, err)
+	_, _, err = client.GetForecastWithMeta(ctx, 18.040468, 59.340379)
+	require.Nil(t, err)
+	_, err = client.GetDailyForecast(ctx, 18.040468, 59.340379)
+	require.Nil(t, err)
+	_, err = client.GetForecastByName(ctx, nil, "Stockholm")
+	require.Nil(t, err)
+	_, err = client.GetGrid(ctx, 18.0, 59.0, 18.2, 59.1, 0.1)
+	require.Nil(t, err)
+	_, err = client.GetForecastRaw(ctx, 18.040468, 59.340379)
+	require.Nil(t, err)
+	var forecast smhi.Forecast
+	require.Nil(t, client.GetForecastInto(ctx, 18.040468, 59.340379, &forecast))
+	require.Equal(t, 12, calls)
+
+	// Retries go through the transport too.
+	calls = 0
+	client = smhi.Client{
+		BaseURL:    "http://smhi.invalid",
+		MaxRetries: 1,
+		HTTPClient: &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calls++
+				if calls == 1 {
+					return &http.Response{
+						StatusCode: http.StatusTooManyRequests,
+						Header:     http.Header{"Retry-After": {"0"}},
+						Body:       io.NopCloser(bytes.NewReader([]byte("slow down"))),
+						Request:    req,
+					}, nil
+				}
+				return &http.Response{
+					StatusCode:    http.StatusOK,
+					Header:        http.Header{},
+					Body:          io.NopCloser(bytes.NewReader(body)),
+					ContentLength: int64(len(body)),
+					Request:       req,
+				}, nil
+			}),
+		},
+	}
+	_, err = client.GetForecast(ctx, 18.040468, 59.340379)
+	require.Nil(t, err)
+	require.Equal(t, 2, calls)
+}
+
+func TestTooManyRequests(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	handler := serveTestdata(t)
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		if n == 1 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		handler(w, r)
+	})
+
+	client := smhi.Client{BaseURL: server.URL, MaxRetries: 1}
+	_, err := client.GetForecast(context.Background(), 18.040468, 59.340379)
+	require.Nil(t, err)
+	require.Equal(t, 2, calls)
+}
+
+func TestRetryAfter(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	})
+
+	client := smhi.Client{BaseURL: server.URL}
+	_, err := client.GetForecast(context.Background(), 18.040468, 59.340379)
+	var apiErr *smhi.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	require.Equal(t, 120*time.Second, apiErr.RetryAfter)
+
+	// Waiting for the retry gives up when the context is done.
+	client = smhi.Client{BaseURL: server.URL, MaxRetries: 1}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = client.GetForecast(ctx, 18.040468, 59.340379)
+	require.ErrorIs(t, err, smhi.ErrTimeout)
+	require.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestGetParameterDescriptions(t *testing.T) {
+	var path string
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"parameter":[` +
+			`{"name":"t","longName":"Air temperature","levelType":"hl","level":2,"unit":"Cel","missingValue":9999},` +
+			`{"name":"new","longName":"New parameter","levelType":"hl","level":0,"unit":"m","missingValue":9999}]}`))
+	})
+
+	client := smhi.Client{BaseURL: server.URL}
+	descriptions, err := client.GetParameterDescriptions(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, "/api/category/pmp3g/version/2/parameter.json", path)
+	require.Len(t, descriptions, 2)
+	require.Equal(t, "Cel", descriptions["t"].Unit)
+	require.Equal(t, smhi.ParameterDescriptions["t"].ValueRange, descriptions["t"].ValueRange)
+	require.Equal(t, "New parameter", descriptions["new"].Description)
+
+	// Throttled requests are retried like forecast requests.
+	throttled := true
+	server = newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if throttled {
+			throttled = false
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"parameter":[{"name":"t","longName":"Air temperature","levelType":"hl","level":2,"unit":"Cel"}]}`))
+	})
+	client = smhi.Client{BaseURL: server.URL, MaxRetries: 1}
+	descriptions, err = client.GetParameterDescriptions(context.Background())
+	require.Nil(t, err)
+	require.Len(t, descriptions, 1)
+
+	broken := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oops", http.StatusInternalServerError)
+	})
+	client = smhi.Client{BaseURL: broken.URL}
+	descriptions, err = client.GetParameterDescriptions(context.Background())
+	require.NotNil(t, err)
+	require.Equal(t, smhi.ParameterDescriptions, descriptions)
+}
+
+func TestGetLatestRadar(t *testing.T) {
+	buf, err := os.ReadFile("testdata/radar.json")
+	require.Nil(t, err)
+	var path string
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write(buf)
+	})
+
+	client := smhi.Client{RadarBaseURL: server.URL}
+	image, err := client.GetLatestRadar(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, "/api/version/latest/area/sweden/product/comp/latest", path)
+	require.Equal(t, "radar_2407130805", image.Key)
+	require.Equal(t, time.Date(2024, 7, 13, 8, 5, 0, 0, time.UTC), image.Valid)
+	require.Len(t, image.URLs, 2)
+	require.Contains(t, image.URLs["png"], "radar_2407130805.png")
+
+	server = newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"key":"radar","formats":[]}`))
+	})
+	client = smhi.Client{RadarBaseURL: server.URL}
+	_, err = client.GetLatestRadar(context.Background())
+	require.NotNil(t, err)
+}
+
+func TestGetHydroWarnings(t *testing.T) {
+	buf, err := os.ReadFile("testdata/warnings.json")
+	require.Nil(t, err)
+	var path string
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write(buf)
+	})
+
+	client := smhi.Client{WarningsBaseURL: server.URL}
+	warnings, err := client.GetHydroWarnings(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, "/ibww/api/version/1/warning.json", path)
+	require.Len(t, warnings, 2)
+
+	require.Equal(t, "High flow", warnings[0].Event)
+	require.Equal(t, "Upper Dalälven", warnings[0].Area)
+	require.Equal(t, []string{"Dalarna County"}, warnings[0].Counties)
+	require.Equal(t, "YELLOW", warnings[0].Severity)
+	require.Equal(t, time.Date(2024, 7, 13, 6, 0, 0, 0, time.UTC), warnings[0].Start)
+	require.Equal(t, time.Date(2024, 7, 15, 18, 0, 0, 0, time.UTC), warnings[0].End)
+
+	require.Equal(t, "Ljusnan", warnings[1].Area)
+	require.Equal(t, "ORANGE", warnings[1].Severity)
+	require.Len(t, warnings[1].Counties, 2)
+	require.True(t, warnings[1].End.IsZero())
+
+	server = newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	client = smhi.Client{WarningsBaseURL: server.URL}
+	_, err = client.GetHydroWarnings(context.Background())
+	require.NotNil(t, err)
+}
+
+type contextKey struct{}
+
+func TestRequestContext(t *testing.T) {
+	server := newTestServer(t, serveTestdata(t))
+
+	var value any
+	client := smhi.Client{
+		BaseURL: server.URL,
+		HTTPClient: &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				value = req.Context().Value(contextKey{})
+				return http.DefaultTransport.RoundTrip(req)
+			}),
+		},
+	}
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "span")
+	_, err := client.GetForecast(ctx, 18.040468, 59.340379)
+	require.Nil(t, err)
+	require.Equal(t, "span", value)
+}
+
+func TestParseForecastResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+	serveTestdata(t)(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	forecast, err := smhi.ParseForecastResponse(rec.Result())
+	require.Nil(t, err)
+	require.Len(t, forecast.TimeSeries, 74)
+
+	rec = httptest.NewRecorder()
+	http.Error(rec, "not found", http.StatusNotFound)
+	_, err = smhi.ParseForecastResponse(rec.Result())
+	require.ErrorIs(t, err, smhi.ErrPointNotCovered)
+}
+
+func newSlowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	return newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+		}
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	server := newSlowServer(t, time.Second)
+
+	client := smhi.Client{BaseURL: server.URL}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.GetForecast(ctx, 18.040468, 59.340379)
+	require.ErrorIs(t, err, smhi.ErrTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	client = smhi.Client{BaseURL: server.URL, HTTPClient: &http.Client{Timeout: 50 * time.Millisecond}}
+	_, err = client.GetForecast(context.Background(), 18.040468, 59.340379)
+	require.ErrorIs(t, err, smhi.ErrTimeout)
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	require.Equal(t, smhi.DefaultTimeout, smhi.DefaultHTTPClient.Timeout)
+
+	server := newSlowServer(t, time.Second)
+
+	timeout := smhi.DefaultHTTPClient.Timeout
+	smhi.DefaultHTTPClient.Timeout = 50 * time.Millisecond
+	t.Cleanup(func() { smhi.DefaultHTTPClient.Timeout = timeout })
+
+	client := smhi.Client{BaseURL: server.URL}
+	_, err := client.GetForecast(context.Background(), 18.040468, 59.340379)
+	require.ErrorIs(t, err, smhi.ErrTimeout)
+}
@@ -1,59 +1,465 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/tomyl/smhi"
 )
 
-func printForecast(forecast *smhi.Forecast) {
+// fileList is a repeatable string flag.
+type fileList []string
+
+func (l *fileList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *fileList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// options controls how forecasts are printed.
+type options struct {
+	// scientific prints SI units, i.e. Kelvin and Pa.
+	scientific bool
+	// markUnreliable marks items beyond Forecast.ReliableUntil.
+	markUnreliable bool
+	// symbols is how weather symbols are printed.
+	symbols smhi.SymbolStyle
+	// loc is the timezone times are printed in. If nil, the timezone of the
+	// forecast point is used.
+	loc *time.Location
+	// color enables ANSI colors.
+	color bool
+	// columns are the names of the columns to print after the time, see
+	// columns.
+	columns []string
+	// now prints only the current conditions, see printCurrent.
+	now bool
+}
+
+// ANSI foreground color codes. All are two digits so that colored cells
+// have the same invisible width and tabwriter keeps the columns aligned.
+const (
+	colorDefault       = 39
+	colorRed           = 31
+	colorGreen         = 32
+	colorYellow        = 33
+	colorBlue          = 34
+	colorMagenta       = 35
+	colorCyan          = 36
+	colorBrightBlue    = 94
+	colorBrightMagenta = 95
+)
+
+// paint wraps s in ANSI escapes for the color code if colors are enabled.
+func (o options) paint(code int, s string) string {
+	if !o.color {
+		return s
+	}
+	return fmt.Sprintf("\x1b[%dm%s\x1b[0m", code, s)
+}
+
+// temperatureColor returns the color for a temperature in °C, from blue
+// when cold to red when hot.
+func temperatureColor(t float64) int {
+	switch {
+	case t < -10:
+		return colorBlue
+	case t < 0:
+		return colorBrightBlue
+	case t < 10:
+		return colorCyan
+	case t < 20:
+		return colorGreen
+	case t < 25:
+		return colorYellow
+	}
+	return colorRed
+}
+
+// precipitationColor returns the color for a precipitation intensity in
+// mm/h, see smhi.RainClass.
+func precipitationColor(p float64) int {
+	switch {
+	case p >= smhi.RainHeavyCutoff:
+		return colorBrightMagenta
+	case p >= smhi.RainModerateCutoff:
+		return colorMagenta
+	case p >= smhi.RainLightCutoff:
+		return colorCyan
+	}
+	return colorDefault
+}
+
+// isTerminal returns whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// location returns the timezone to print the forecast's times in.
+func (o options) location(forecast *smhi.Forecast) *time.Location {
+	if o.loc != nil {
+		return o.loc
+	}
+	loc, err := forecast.Location()
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// symbol returns the weather symbol in the configured style.
+func (o options) symbol(s smhi.WeatherSymbol) string {
+	if o.symbols == smhi.SymbolEmoji {
+		return s.FixedWidth()
+	}
+	return s.Render(o.symbols)
+}
+
+// column is a column in the forecast table.
+type column struct {
+	header string
+	value  func(opts options, item smhi.TimeSeriesItem) string
+	// color, if set, returns the color of the value.
+	color func(item smhi.TimeSeriesItem) int
+}
+
+// columns are the columns selectable with -columns.
+var columns = map[string]column{
+	"weather": {
+		header: "Weather",
+		value: func(opts options, item smhi.TimeSeriesItem) string {
+			weather := item.WeatherSymbol()
+			return opts.symbol(weather) + " " + weather.Meaning
+		},
+	},
+	"temperature": {
+		header: "Temperature",
+		value: func(opts options, item smhi.TimeSeriesItem) string {
+			if opts.scientific {
+				return fmt.Sprintf("%.2f K", item.TemperatureK())
+			}
+			return fmt.Sprintf("%.1f°C", item.Temperature())
+		},
+		color: func(item smhi.TimeSeriesItem) int { return temperatureColor(item.Temperature()) },
+	},
+	"precipitation": {
+		header: "Max precipitation",
+		value: func(opts options, item smhi.TimeSeriesItem) string {
+			return fmt.Sprintf("%.1f mm/h", item.MaxPrecipitation())
+		},
+		color: func(item smhi.TimeSeriesItem) int { return precipitationColor(item.MaxPrecipitation()) },
+	},
+	"pcat": {
+		header: "Precipitation",
+		value: func(opts options, item smhi.TimeSeriesItem) string {
+			return item.PrecipitationCategory().String()
+		},
+	},
+	"wind": {
+		header: "Wind speed",
+		value: func(opts options, item smhi.TimeSeriesItem) string {
+			return fmt.Sprintf("%.1f m/s", item.WindSpeed())
+		},
+	},
+	"pressure": {
+		header: "Pressure",
+		value: func(opts options, item smhi.TimeSeriesItem) string {
+			if opts.scientific {
+				return fmt.Sprintf("%.0f Pa", item.Pressure()*100)
+			}
+			return fmt.Sprintf("%.0f hPa", item.Pressure())
+		},
+	},
+}
+
+// defaultColumns are printed unless -columns is set.
+var defaultColumns = []string{"weather", "temperature", "precipitation", "wind"}
+
+// parseColumns parses a comma separated list of column names.
+func parseColumns(value string) ([]string, error) {
+	names := strings.Split(value, ",")
+	for idx, name := range names {
+		name = strings.TrimSpace(name)
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("unknown column: %s", name)
+		}
+		names[idx] = name
+	}
+	return names, nil
+}
+
+func printForecast(forecast *smhi.Forecast, opts options) {
 	w := tabwriter.NewWriter(os.Stdout, 1, 1, 2, ' ', 0)
-	fmt.Fprintf(w, "Time\tWeather\tTemperature\tMax precipitation\tWind speed\n")
 
+	fmt.Fprint(w, "Time")
+	for _, name := range opts.columns {
+		col := columns[name]
+		header := col.header
+		if col.color != nil {
+			header = opts.paint(colorDefault, header)
+		}
+		fmt.Fprintf(w, "\t%s", header)
+	}
+	fmt.Fprintln(w)
+
+	loc := opts.location(forecast)
+	unreliable := false
 	for _, item := range forecast.TimeSeries {
-		ts := item.ValidTime.Local().Format("Mon 15:04")
-		weather := item.WeatherSymbol()
-		fmt.Fprintf(w, "%s\t%s %s\t%.1f°C\t%.1f mm/h\t%.1f m/s\n", ts, weather.FixedWidth(), weather.Meaning, item.Temperature(), item.MaxPrecipitation(), item.WindSpeed())
+		ts := item.ValidTime.In(loc).Format("Mon 15:04")
+		if opts.markUnreliable && item.ValidTime.After(forecast.ReliableUntil()) {
+			ts += "*"
+			unreliable = true
+		}
+		fmt.Fprint(w, ts)
+		for _, name := range opts.columns {
+			col := columns[name]
+			value := col.value(opts, item)
+			if col.color != nil {
+				value = opts.paint(col.color(item), value)
+			}
+			fmt.Fprintf(w, "\t%s", value)
+		}
+		fmt.Fprintln(w)
 	}
 
 	w.Flush()
+
+	if unreliable {
+		fmt.Println("* less reliable, more than 5 days ahead")
+	}
+}
+
+// printCurrent prints the current conditions on one line, e.g.
+// "🌧 12°C ↗5m/s".
+func printCurrent(forecast *smhi.Forecast, opts options) {
+	item, ok := forecast.Current(time.Now())
+	if !ok {
+		return
+	}
+	temperature := fmt.Sprintf("%.0f°C", item.Temperature())
+	if opts.scientific {
+		temperature = fmt.Sprintf("%.0f K", item.TemperatureK())
+	}
+	fmt.Printf("%s %s %c%.0fm/s\n", item.WeatherSymbol().Render(opts.symbols), temperature, item.WindArrow(), item.WindSpeed())
+}
+
+// show prints the forecast as configured by opts.
+func show(forecast *smhi.Forecast, opts options) {
+	if opts.now {
+		printCurrent(forecast, opts)
+	} else {
+		printForecast(forecast, opts)
+	}
+}
+
+func printDescriptions() {
+	names := make([]string, 0, len(smhi.ParameterDescriptions))
+	for name, desc := range smhi.ParameterDescriptions {
+		// Skip aliases such as the old "wsymb2" key.
+		if name == desc.Name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 2, ' ', 0)
+	fmt.Fprintf(w, "Name\tDescription\tUnit\tValue range\n")
+
+	for _, name := range names {
+		desc := smhi.ParameterDescriptions[name]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", desc.Name, desc.Description, desc.Unit, desc.ValueRange)
+	}
+
+	w.Flush()
+}
+
+func readForecast(name string) (*smhi.Forecast, error) {
+	buf, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	forecast, err := smhi.ParseForecast(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return forecast, nil
+}
+
+func printFiles(names []string, opts options) error {
+	for i, name := range names {
+		forecast, err := readForecast(name)
+		if err != nil {
+			return err
+		}
+		if err := checkForecast(forecast); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if len(names) > 1 {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("==> %s <==\n", name)
+		}
+		show(forecast, opts)
+	}
+	return nil
+}
+
+// errUsage is returned by run when the flags are insufficient.
+var errUsage = errors.New("set -lon and -lat, or -file")
+
+// errBadData is returned by run when a forecast is empty or truncated.
+var errBadData = errors.New("bad forecast data")
+
+// minItems is the least number of timeseries items expected in a forecast.
+// SMHI returns about 70 items so fewer than a day of hourly items means the
+// data is truncated.
+const minItems = 24
+
+func checkForecast(forecast *smhi.Forecast) error {
+	if n := len(forecast.TimeSeries); n < minItems {
+		return fmt.Errorf("%w: %d timeseries items, expected at least %d", errBadData, n, minItems)
+	}
+	return nil
 }
 
 func run() error {
+	var names fileList
 	lon := flag.Float64("lon", 0, "Longitude")
 	lat := flag.Float64("lat", 0, "Latitude")
-	name := flag.String("file", "", "Read data from file")
+	flag.Var(&names, "file", "Read data from file (repeatable)")
+	dir := flag.String("dir", "", "Read data from all .json files in directory")
+	describe := flag.Bool("describe", false, "Print parameter descriptions")
+	units := flag.String("units", "metric", "Units: metric or scientific (Kelvin and Pa)")
+	markUnreliable := flag.Bool("mark-unreliable", false, "Mark items more than 5 days ahead")
+	symbols := flag.String("symbols", "emoji", "Weather symbols: emoji, text or ascii")
+	tz := flag.String("tz", "", "Timezone, e.g. UTC or Local (default the forecast point's timezone)")
+	color := flag.String("color", "auto", "Colors: auto (if stdout is a terminal), always or never")
+	cols := flag.String("columns", "", "Comma separated columns: weather, temperature, precipitation, pcat, wind, pressure (default weather,temperature,precipitation,wind and pressure with scientific units)")
+	raw := flag.Bool("raw", false, "Print the response body as fetched from SMHI")
+	now := flag.Bool("now", false, "Print only the current conditions on one line")
 	flag.Parse()
 
-	if *name != "" {
-		buf, err := os.ReadFile(*name)
+	opts := options{markUnreliable: *markUnreliable, now: *now}
+	switch *units {
+	case "metric":
+	case "scientific":
+		opts.scientific = true
+	default:
+		return fmt.Errorf("unknown units: %s", *units)
+	}
+
+	if *cols != "" {
+		names, err := parseColumns(*cols)
 		if err != nil {
 			return err
 		}
-		var forecast smhi.Forecast
-		if err := json.Unmarshal(buf, &forecast); err != nil {
+		opts.columns = names
+	} else {
+		opts.columns = defaultColumns
+		if opts.scientific {
+			opts.columns = append(opts.columns, "pressure")
+		}
+	}
+
+	switch *symbols {
+	case "emoji":
+		opts.symbols = smhi.SymbolEmoji
+	case "text":
+		opts.symbols = smhi.SymbolText
+	case "ascii":
+		opts.symbols = smhi.SymbolASCII
+	default:
+		return fmt.Errorf("unknown symbols: %s", *symbols)
+	}
+
+	switch *color {
+	case "auto":
+		opts.color = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+	case "always":
+		opts.color = true
+	case "never":
+	default:
+		return fmt.Errorf("unknown color: %s", *color)
+	}
+
+	if *tz != "" {
+		loc, err := time.LoadLocation(*tz)
+		if err != nil {
 			return err
 		}
-		printForecast(&forecast)
+		opts.loc = loc
+	}
+
+	if *describe {
+		printDescriptions()
 		return nil
 	}
 
+	if *dir != "" {
+		matches, err := filepath.Glob(filepath.Join(*dir, "*.json"))
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("no .json files in %s", *dir)
+		}
+		names = append(names, matches...)
+	}
+
+	if len(names) > 0 {
+		return printFiles(names, opts)
+	}
+
+	if *lon == 0 && *lat == 0 {
+		return errUsage
+	}
+
+	if *raw {
+		var client smhi.Client
+		buf, err := client.GetForecastRaw(context.Background(), *lon, *lat)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(buf)
+		return err
+	}
+
 	forecast, err := smhi.GetForecast(*lon, *lat)
 	if err != nil {
 		return err
 	}
 
-	printForecast(forecast)
+	if err := checkForecast(forecast); err != nil {
+		return err
+	}
+
+	show(forecast, opts)
 	return nil
 }
 
 func main() {
 	if err := run(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, errUsage) {
+			flag.Usage()
+			os.Exit(2)
+		}
+		if errors.Is(err, errBadData) {
+			os.Exit(2)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,80 @@
+package smhi
+
+import (
+	"math"
+	"time"
+)
+
+// Weights used by ComfortIndex.
+const (
+	// ComfortIdealTemperature is the temperature in °C that scores best.
+	ComfortIdealTemperature = 21.0
+	// ComfortTemperatureWeight is the penalty per °C from the ideal.
+	ComfortTemperatureWeight = 4.0
+	// ComfortIdealHumidity is the relative humidity in % that scores best.
+	ComfortIdealHumidity = 50.0
+	// ComfortHumidityWeight is the penalty per percentage point from the
+	// ideal.
+	ComfortHumidityWeight = 0.5
+	// ComfortWindWeight is the penalty per m/s of wind speed.
+	ComfortWindWeight = 3.0
+)
+
+// ComfortIndex returns a pleasantness score from 0 (awful) to 100 (perfect)
+// for this forecast timeseries item. It is computed as
+//
+//	100 - ComfortTemperatureWeight * |t - ComfortIdealTemperature|
+//	    - ComfortHumidityWeight * |r - ComfortIdealHumidity|
+//	    - ComfortWindWeight * ws
+//
+// rounded and clamped to 0-100.
+func (i TimeSeriesItem) ComfortIndex() int {
+	score := 100 -
+		ComfortTemperatureWeight*math.Abs(i.Temperature()-ComfortIdealTemperature) -
+		ComfortHumidityWeight*math.Abs(i.Float64(ParamHumidity)-ComfortIdealHumidity) -
+		ComfortWindWeight*i.WindSpeed()
+	return int(math.Round(max(0, min(100, score))))
+}
+
+// FeelsLike returns the apparent temperature in °C for this forecast
+// timeseries item. At 10°C or below with a wind speed of at least 1.3 m/s it
+// is the wind chill (the formula used by SMHI and the North American weather
+// services). At 27°C or above it is the heat index (the Rothfusz regression
+// used by the US National Weather Service), if the item has the relative
+// humidity. Otherwise it is the air temperature.
+func (i TimeSeriesItem) FeelsLike() float64 {
+	t := i.Temperature()
+	if ws := i.WindSpeed(); t <= 10 && ws >= 1.3 {
+		v := math.Pow(ws*3.6, 0.16)
+		return 13.12 + 0.6215*t - 11.37*v + 0.3965*t*v
+	}
+	if r, ok := i.Lookup(ParamHumidity); ok && t >= 27 {
+		f := t*9/5 + 32
+		hi := -42.379 + 2.04901523*f + 10.14333127*r - 0.22475541*f*r -
+			0.00683783*f*f - 0.05481717*r*r + 0.00122874*f*f*r +
+			0.00085282*f*r*r - 0.00000199*f*f*r*r
+		return (hi - 32) * 5 / 9
+	}
+	return t
+}
+
+// FeelsLikePoint is the apparent temperature at a point in time.
+type FeelsLikePoint struct {
+	Time time.Time
+	// Temperature is the apparent temperature in °C, see
+	// TimeSeriesItem.FeelsLike.
+	Temperature float64
+}
+
+// FeelsLikeSeries returns the apparent temperature at each valid time, e.g.
+// for a chart. Items without the air temperature are skipped.
+func (f *Forecast) FeelsLikeSeries() []FeelsLikePoint {
+	var points []FeelsLikePoint
+	for _, item := range f.TimeSeries {
+		if _, ok := item.Lookup(ParamTemperature); !ok {
+			continue
+		}
+		points = append(points, FeelsLikePoint{Time: item.ValidTime, Temperature: item.FeelsLike()})
+	}
+	return points
+}
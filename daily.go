@@ -0,0 +1,185 @@
+package smhi
+
+import (
+	"context"
+	"time"
+)
+
+// ByDay groups the timeseries items by calendar day in loc.
+func (f *Forecast) ByDay(loc *time.Location) [][]TimeSeriesItem {
+	var days [][]TimeSeriesItem
+	var y, d int
+	var m time.Month
+	for _, item := range f.TimeSeries {
+		iy, im, id := item.ValidTime.In(loc).Date()
+		if len(days) == 0 || iy != y || im != m || id != d {
+			days = append(days, nil)
+			y, m, d = iy, im, id
+		}
+		days[len(days)-1] = append(days[len(days)-1], item)
+	}
+	return days
+}
+
+// DailySummary summarizes the forecast for a calendar day.
+type DailySummary struct {
+	// Date is midnight at the start of the day.
+	Date           time.Time
+	MinTemperature float64
+	MaxTemperature float64
+	// Precipitation is the total mean precipitation in mm.
+	Precipitation float64
+	MaxWindSpeed  float64
+	// Symbol is the most severe weather symbol during the day.
+	Symbol WeatherSymbol
+}
+
+// DailyForecast is a forecast summarized per calendar day.
+type DailyForecast struct {
+	ApprovedTime  time.Time
+	ReferenceTime time.Time
+	Days          []DailySummary
+}
+
+// Daily summarizes the forecast per calendar day in loc. The precipitation
+// total accumulates the mean precipitation intensity of each item over the
+// time until the next item and attributes it to the day of the item.
+func (f *Forecast) Daily(loc *time.Location) *DailyForecast {
+	daily := &DailyForecast{
+		ApprovedTime:  f.ApprovedTime,
+		ReferenceTime: f.ReferenceTime,
+	}
+
+	idx := 0
+	for _, items := range f.ByDay(loc) {
+		y, m, d := items[0].ValidTime.In(loc).Date()
+		day := DailySummary{
+			Date:           time.Date(y, m, d, 0, 0, 0, 0, loc),
+			MinTemperature: items[0].Temperature(),
+			MaxTemperature: items[0].Temperature(),
+			Symbol:         mostSevere(items),
+		}
+		for _, item := range items {
+			day.MinTemperature = min(day.MinTemperature, item.Temperature())
+			day.MaxTemperature = max(day.MaxTemperature, item.Temperature())
+			day.MaxWindSpeed = max(day.MaxWindSpeed, item.WindSpeed())
+			day.Precipitation += item.Float64(ParamMeanPrecipitation) * f.stepDuration(idx).Hours()
+			idx++
+		}
+		daily.Days = append(daily.Days, day)
+	}
+
+	return daily
+}
+
+// DayMean is the mean temperature of a calendar day.
+type DayMean struct {
+	// Date is midnight at the start of the day.
+	Date time.Time
+	// Temperature is the mean temperature in °C.
+	Temperature float64
+}
+
+// DailyMeanTemperature returns the mean temperature per calendar day in loc.
+// Each item is weighted by how long it is in effect during the day since
+// the step length grows from 1 hour to 12 hours across the forecast. The
+// first and last days are only partially covered by the forecast so their
+// means only cover the forecast part of the day.
+func (f *Forecast) DailyMeanTemperature(loc *time.Location) []DayMean {
+	var means []DayMean
+	for _, items := range f.ByDay(loc) {
+		y, m, d := items[0].ValidTime.In(loc).Date()
+		start := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		items, weights := f.windowWeights(start, start.AddDate(0, 0, 1))
+		var sum, total float64
+		for idx, item := range items {
+			sum += weights[idx] * item.Temperature()
+			total += weights[idx]
+		}
+		means = append(means, DayMean{Date: start, Temperature: sum / total})
+	}
+	return means
+}
+
+// DayRange is the diurnal temperature range of a calendar day.
+type DayRange struct {
+	// Date is midnight at the start of the day.
+	Date time.Time
+	// Range is the maximum minus the minimum temperature in °C.
+	Range float64
+}
+
+// DiurnalRange returns the difference between the maximum and minimum
+// temperature per calendar day in loc, see Daily. Days only partially
+// covered by the forecast, typically the first and last, may have a
+// smaller range than the whole day would.
+func (f *Forecast) DiurnalRange(loc *time.Location) []DayRange {
+	days := f.Daily(loc).Days
+	ranges := make([]DayRange, len(days))
+	for idx, day := range days {
+		ranges[idx] = DayRange{Date: day.Date, Range: day.MaxTemperature - day.MinTemperature}
+	}
+	return ranges
+}
+
+// GetDailyForecast requests the forecast for a longitude/latitude coordinate
+// and summarizes it per calendar day in time.Local. SMHI doesn't provide a
+// daily product for points so the summary is derived from the detailed
+// timeseries, see Forecast.Daily.
+func (c *Client) GetDailyForecast(ctx context.Context, lon, lat float64) (*DailyForecast, error) {
+	forecast, err := c.GetForecast(ctx, lon, lat)
+	if err != nil {
+		return nil, err
+	}
+
+	return forecast.Daily(time.Local), nil
+}
+
+// GetDailyForecast requests the forecast for a longitude/latitude coordinate
+// and summarizes it per calendar day in time.Local. It uses a zero value
+// Client.
+func GetDailyForecast(lon, lat float64) (*DailyForecast, error) {
+	var c Client
+	return c.GetDailyForecast(context.Background(), lon, lat)
+}
+
+// Day returns the timeseries items for the calendar day offset days from
+// today in loc, i.e. 0 is today and 1 is tomorrow. Returns false if the
+// forecast doesn't cover the day.
+func (f *Forecast) Day(offset int, loc *time.Location) ([]TimeSeriesItem, bool) {
+	return f.DayAt(time.Now(), offset, loc)
+}
+
+// DayAt is like Day but relative to the day of now instead of today.
+func (f *Forecast) DayAt(now time.Time, offset int, loc *time.Location) ([]TimeSeriesItem, bool) {
+	y, m, d := now.In(loc).Date()
+	items := f.itemsOn(time.Date(y, m, d+offset, 12, 0, 0, 0, loc), loc)
+	return items, len(items) > 0
+}
+
+// DryDayStreak returns the number of consecutive calendar days in loc,
+// starting today, where the total precipitation (see Daily) is below
+// threshold mm. Counting stops at the first wet day or the end of the
+// forecast.
+func (f *Forecast) DryDayStreak(loc *time.Location, threshold float64) int {
+	return f.DryDayStreakAt(time.Now(), loc, threshold)
+}
+
+// DryDayStreakAt is like DryDayStreak but starts on the day of now instead of
+// today.
+func (f *Forecast) DryDayStreakAt(now time.Time, loc *time.Location, threshold float64) int {
+	y, m, d := now.In(loc).Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, loc)
+
+	streak := 0
+	for _, day := range f.Daily(loc).Days {
+		if day.Date.Before(today) {
+			continue
+		}
+		if !day.Date.Equal(today.AddDate(0, 0, streak)) || day.Precipitation >= threshold {
+			break
+		}
+		streak++
+	}
+	return streak
+}
@@ -0,0 +1,143 @@
+package smhi
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// DefaultPrecision is the number of decimals numbers are rounded to by
+// SimpleJSON, WriteNDJSON and Rows. SMHI's values have at most one decimal.
+const DefaultPrecision = 1
+
+// round rounds x to the number of decimals. Negative decimals leave x as is.
+func round(x float64, decimals int) float64 {
+	if decimals < 0 {
+		return x
+	}
+	p := math.Pow10(decimals)
+	return math.Round(x*p) / p
+}
+
+// flatten returns the item as a flat map of its parameters by name, its
+// valid time and the derived weather symbol meaning and precipitation
+// category. Parameter values are rounded to the number of decimals.
+func (i TimeSeriesItem) flatten(decimals int) map[string]any {
+	m := map[string]any{
+		"validTime":             i.ValidTime,
+		"weatherSymbol":         i.WeatherSymbol().Meaning,
+		"precipitationCategory": i.PrecipitationCategory().String(),
+	}
+	for _, p := range i.Parameters {
+		if len(p.Values) > 0 {
+			m[p.Name] = round(p.Values[0], decimals)
+		}
+	}
+	return m
+}
+
+// WriteNDJSON writes the forecast as newline delimited JSON, one object per
+// timeseries item. Each object has the item's valid time, its parameters by
+// name, and the derived fields weatherSymbol and precipitationCategory.
+// Numbers are rounded to DefaultPrecision decimals.
+func (f *Forecast) WriteNDJSON(w io.Writer) error {
+	return f.WriteNDJSONPrecision(w, DefaultPrecision)
+}
+
+// WriteNDJSONPrecision is like WriteNDJSON but rounds numbers to the given
+// number of decimals, or not at all if negative.
+func (f *Forecast) WriteNDJSONPrecision(w io.Writer, decimals int) error {
+	enc := json.NewEncoder(w)
+	for _, item := range f.TimeSeries {
+		if err := enc.Encode(item.flatten(decimals)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// simpleItem is the JSON shape of an item in SimpleJSON.
+type simpleItem struct {
+	Time         time.Time `json:"time"`
+	TemperatureC float64   `json:"temperatureC"`
+	WindMps      float64   `json:"windMps"`
+	WindDir      int       `json:"windDir"`
+	PrecipMmH    float64   `json:"precipMmH"`
+	Symbol       int       `json:"symbol"`
+	SymbolText   string    `json:"symbolText"`
+}
+
+// SimpleJSON returns the forecast as a JSON array with one flat object per
+// timeseries item, e.g. for web frontends. The fields are time,
+// temperatureC, windMps, windDir (degrees), precipMmH (the mean
+// precipitation intensity), symbol (the weather symbol code) and symbolText.
+// Numbers are rounded to DefaultPrecision decimals.
+func (f *Forecast) SimpleJSON() ([]byte, error) {
+	return f.SimpleJSONPrecision(DefaultPrecision)
+}
+
+// SimpleJSONPrecision is like SimpleJSON but rounds numbers to the given
+// number of decimals, or not at all if negative.
+func (f *Forecast) SimpleJSONPrecision(decimals int) ([]byte, error) {
+	items := make([]simpleItem, len(f.TimeSeries))
+	for idx, item := range f.TimeSeries {
+		symbol := item.WeatherSymbol()
+		items[idx] = simpleItem{
+			Time:         item.ValidTime,
+			TemperatureC: round(item.Temperature(), decimals),
+			WindMps:      round(item.WindSpeed(), decimals),
+			WindDir:      item.WindDirection(),
+			PrecipMmH:    round(item.Float64(ParamMeanPrecipitation), decimals),
+			Symbol:       symbol.Value,
+			SymbolText:   symbol.Meaning,
+		}
+	}
+	return json.Marshal(items)
+}
+
+// formatValue formats a parameter value rounded to the number of decimals
+// with the unit from ParameterDescriptions, e.g. "18.6 C". Codes and
+// categories have no unit.
+func formatValue(name string, value float64, decimals int) string {
+	s := strconv.FormatFloat(round(value, decimals), 'f', -1, 64)
+	switch unit := ParameterDescriptions[name].Unit; unit {
+	case "", "code", "category":
+		return s
+	case "%":
+		return s + unit
+	default:
+		return s + " " + unit
+	}
+}
+
+// Rows returns the forecast as a table for table and TUI libraries. The
+// header is "Time" followed by the parameter names in columns, e.g.
+// ParamTemperature. Each row has the valid time in loc followed by the
+// parameter values with units, e.g. "18.6 C". Missing parameters are empty.
+// Numbers are rounded to DefaultPrecision decimals.
+func (f *Forecast) Rows(columns []string, loc *time.Location) ([]string, [][]string) {
+	return f.RowsPrecision(columns, loc, DefaultPrecision)
+}
+
+// RowsPrecision is like Rows but rounds numbers to the given number of
+// decimals, or not at all if negative.
+func (f *Forecast) RowsPrecision(columns []string, loc *time.Location, decimals int) ([]string, [][]string) {
+	header := append([]string{"Time"}, columns...)
+	rows := make([][]string, len(f.TimeSeries))
+	for idx, item := range f.TimeSeries {
+		row := make([]string, 0, len(header))
+		row = append(row, item.ValidTime.In(loc).Format("2006-01-02 15:04"))
+		for _, name := range columns {
+			value, ok := item.Lookup(name)
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, formatValue(name, value, decimals))
+		}
+		rows[idx] = row
+	}
+	return header, rows
+}
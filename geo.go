@@ -0,0 +1,64 @@
+package smhi
+
+import "math"
+
+// EarthRadius is the mean radius of the Earth in metres.
+const EarthRadius = 6371000.0
+
+// Distance returns the great-circle distance in metres between two
+// longitude/latitude coordinates, using the haversine formula.
+func Distance(lon1, lat1, lon2, lat2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Sqrt(a))
+}
+
+// Grid cell size in degrees used by GridCell. SMHI's forecast model has a
+// grid spacing of about 2.5 km, which is about 0.0225° of latitude and, in
+// southern and central Sweden, 0.045° of longitude.
+const (
+	GridCellLon = 0.045
+	GridCellLat = 0.0225
+)
+
+// GridCell returns the center of the grid cell containing a
+// longitude/latitude coordinate. The model grid is a projected grid, so this
+// regular lon/lat grid of GridCellLon by GridCellLat degrees only
+// approximates it. It is suitable as a cache key since coordinates in the
+// same cell get nearly identical forecasts.
+func GridCell(lon, lat float64) Point {
+	return Point{
+		(math.Floor(lon/GridCellLon) + 0.5) * GridCellLon,
+		(math.Floor(lat/GridCellLat) + 0.5) * GridCellLat,
+	}
+}
+
+// SameGridCell returns true if two longitude/latitude coordinates are in the
+// same grid cell. See GridCell.
+func SameGridCell(lon1, lat1, lon2, lat2 float64) bool {
+	return GridCell(lon1, lat1) == GridCell(lon2, lat2)
+}
+
+// ResolvedPoint returns the grid point SMHI resolved the requested
+// coordinate to. Returns false if the forecast has no geometry, e.g. for
+// trimmed fixtures.
+func (f *Forecast) ResolvedPoint() (Point, bool) {
+	if len(f.Geometry.Coordinates) == 0 {
+		return Point{}, false
+	}
+	return f.Geometry.Coordinates[0], true
+}
+
+// OffsetFrom returns the distance in metres from a longitude/latitude
+// coordinate, typically the requested one, to the resolved point. Returns
+// false if the forecast has no geometry.
+func (f *Forecast) OffsetFrom(lon, lat float64) (float64, bool) {
+	p, ok := f.ResolvedPoint()
+	if !ok {
+		return 0, false
+	}
+	return Distance(lon, lat, p[0], p[1]), true
+}
@@ -0,0 +1,59 @@
+package smhi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownPlace is returned by StaticGeocoder for names it doesn't know.
+var ErrUnknownPlace = errors.New("unknown place")
+
+// Geocoder resolves a place name to a longitude/latitude coordinate.
+type Geocoder interface {
+	Lookup(name string) (lon, lat float64, err error)
+}
+
+// StaticGeocoder is a Geocoder backed by a fixed table. Keys must be lower
+// case, lookups are case insensitive.
+type StaticGeocoder map[string]Point
+
+// Lookup returns the coordinate for name.
+func (g StaticGeocoder) Lookup(name string) (float64, float64, error) {
+	p, ok := g[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: %s", ErrUnknownPlace, name)
+	}
+	return p[0], p[1], nil
+}
+
+// SwedishCities is a StaticGeocoder for a handful of Swedish cities.
+var SwedishCities = StaticGeocoder{
+	"stockholm":  {18.0686, 59.3293},
+	"göteborg":   {11.9746, 57.7089},
+	"gothenburg": {11.9746, 57.7089},
+	"malmö":      {13.0038, 55.6050},
+	"uppsala":    {17.6389, 59.8586},
+	"linköping":  {15.6214, 58.4108},
+	"örebro":     {15.2134, 59.2753},
+	"visby":      {18.2948, 57.6348},
+	"umeå":       {20.2630, 63.8258},
+	"luleå":      {22.1567, 65.5848},
+	"kiruna":     {20.2253, 67.8558},
+}
+
+// GetForecastByName resolves name with geocoder and requests the forecast
+// for the resulting coordinate. If geocoder is nil, SwedishCities is used.
+func (c *Client) GetForecastByName(ctx context.Context, geocoder Geocoder, name string) (*Forecast, error) {
+	if geocoder == nil {
+		geocoder = SwedishCities
+	}
+
+	lon, lat, err := geocoder.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetForecast(ctx, lon, lat)
+}
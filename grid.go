@@ -0,0 +1,113 @@
+package smhi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// DefaultGridWorkers is the number of concurrent requests GetGrid makes if
+// Client.GridWorkers is zero.
+const DefaultGridWorkers = 4
+
+// MaxGridPoints is the largest number of points GetGrid requests forecasts
+// for.
+const MaxGridPoints = 10000
+
+// gridSize returns the number of points along each axis from min to max in
+// steps of step. The counts are floats so that tiny steps don't overflow.
+func gridSize(minLon, minLat, maxLon, maxLat, step float64) (float64, float64) {
+	nLon := math.Floor((maxLon-minLon)/step+1e-9) + 1
+	nLat := math.Floor((maxLat-minLat)/step+1e-9) + 1
+	return nLon, nLat
+}
+
+// gridPoints returns the points from min to max in steps of step. The
+// coordinates are rounded to the precision used in request URLs. The caller
+// must check that the grid isn't too large.
+func gridPoints(minLon, minLat, maxLon, maxLat, step float64) []Point {
+	round := func(v float64) float64 {
+		return math.Round(v*1e6) / 1e6
+	}
+	fLon, fLat := gridSize(minLon, minLat, maxLon, maxLat, step)
+	nLon, nLat := int(fLon), int(fLat)
+	points := make([]Point, 0, nLon*nLat)
+	for i := 0; i < nLat; i++ {
+		for j := 0; j < nLon; j++ {
+			points = append(points, Point{round(minLon + float64(j)*step), round(minLat + float64(i)*step)})
+		}
+	}
+	return points
+}
+
+// GetGrid requests forecasts for a grid of points from minLon/minLat to
+// maxLon/maxLat in steps of step degrees. At most GridWorkers requests are
+// made concurrently. The forecasts that could be fetched are returned keyed
+// by point even if others failed, in which case the error describes the
+// failed points. Grids of more than MaxGridPoints points are rejected.
+func (c *Client) GetGrid(ctx context.Context, minLon, minLat, maxLon, maxLat, step float64) (map[Point]*Forecast, error) {
+	if !(step > 0) || math.IsInf(step, 1) {
+		return nil, fmt.Errorf("step must be positive and finite: %f", step)
+	}
+	if err := validateCoordinate(minLon, minLat); err != nil {
+		return nil, err
+	}
+	if err := validateCoordinate(maxLon, maxLat); err != nil {
+		return nil, err
+	}
+	if maxLon < minLon || maxLat < minLat {
+		return nil, errors.New("max coordinate is less than min coordinate")
+	}
+	if nLon, nLat := gridSize(minLon, minLat, maxLon, maxLat, step); nLon*nLat > MaxGridPoints {
+		return nil, fmt.Errorf("grid has %.0f points, more than %d", nLon*nLat, MaxGridPoints)
+	}
+
+	workers := c.GridWorkers
+	if workers <= 0 {
+		workers = DefaultGridWorkers
+	}
+
+	points := make(chan Point)
+	go func() {
+		defer close(points)
+		for _, p := range gridPoints(minLon, minLat, maxLon, maxLat, step) {
+			select {
+			case points <- p:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	forecasts := make(map[Point]*Forecast)
+	var errs []error
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range points {
+				forecast, err := c.GetForecast(ctx, p[0], p[1])
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%f,%f: %w", p[0], p[1], err))
+				} else {
+					forecasts[p] = forecast
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return forecasts, errors.Join(errs...)
+}
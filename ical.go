@@ -0,0 +1,110 @@
+package smhi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StrongWind is the wind speed in m/s from which ICalendar reports strong
+// wind. This is the lower limit of "kuling" (gale) in Swedish forecasts.
+const StrongWind = 14.0
+
+// calendarEvent is a notable weather event.
+type calendarEvent struct {
+	kind    string
+	summary string
+	window  TimeWindow
+}
+
+func (f *Forecast) calendarEvents() []calendarEvent {
+	var events []calendarEvent
+	add := func(kind string, match func(TimeSeriesItem) bool, summary func(items []TimeSeriesItem) string) {
+		for _, run := range f.runs(match) {
+			events = append(events, calendarEvent{
+				kind:    kind,
+				summary: summary(f.TimeSeries[run[0] : run[1]+1]),
+				window:  f.runWindow(run[0], run[1]),
+			})
+		}
+	}
+
+	add("precipitation", func(i TimeSeriesItem) bool { return i.Float64(ParamMeanPrecipitation) > 0 }, func(items []TimeSeriesItem) string {
+		var pmean float64
+		for _, item := range items {
+			pmean = max(pmean, item.Float64(ParamMeanPrecipitation))
+		}
+		return fmt.Sprintf("%s, up to %.1f mm/h", items[0].PrecipitationCategory(), pmean)
+	})
+	add("frost", func(i TimeSeriesItem) bool { return i.Temperature() < 0 }, func(items []TimeSeriesItem) string {
+		t := items[0].Temperature()
+		for _, item := range items {
+			t = min(t, item.Temperature())
+		}
+		return fmt.Sprintf("Frost, down to %.1f°C", t)
+	})
+	add("wind", func(i TimeSeriesItem) bool { return i.WindSpeed() >= StrongWind }, func(items []TimeSeriesItem) string {
+		var gust float64
+		for _, item := range items {
+			gust = max(gust, item.Float64(ParamGust))
+		}
+		return fmt.Sprintf("Strong wind, gusts up to %.0f m/s", gust)
+	})
+	add("thunder", func(i TimeSeriesItem) bool {
+		v := i.WeatherSymbol().Value
+		return v == 11 || v == 21
+	}, func(items []TimeSeriesItem) string {
+		return "Thunder"
+	})
+
+	return events
+}
+
+// icalEscape escapes text values according to RFC 5545.
+var icalEscape = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+const icalTime = "20060102T150405Z"
+
+// ICalendar returns notable weather events as an iCalendar (RFC 5545)
+// document with one VEVENT per event. Events are periods of precipitation,
+// frost (below 0°C), strong wind (see StrongWind) and thunder. Times are in
+// UTC, loc is used for the local times in the event descriptions.
+func (f *Forecast) ICalendar(loc *time.Location) ([]byte, error) {
+	if loc == nil {
+		return nil, errors.New("location is nil")
+	}
+
+	var buf bytes.Buffer
+	line := func(format string, args ...any) {
+		fmt.Fprintf(&buf, format+"\r\n", args...)
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//tomyl//smhi//EN")
+
+	// UIDs must be globally unique so include the forecast point, or
+	// events of different locations in the same calendar collide.
+	var point string
+	if p, ok := f.ResolvedPoint(); ok {
+		point = fmt.Sprintf("-%.4f_%.4f", p[0], p[1])
+	}
+
+	for _, event := range f.calendarEvents() {
+		start, end := event.window.Start, event.window.End
+		line("BEGIN:VEVENT")
+		line("UID:%s-%d%s@smhi", event.kind, start.Unix(), point)
+		line("DTSTAMP:%s", f.ApprovedTime.UTC().Format(icalTime))
+		line("DTSTART:%s", start.UTC().Format(icalTime))
+		line("DTEND:%s", end.UTC().Format(icalTime))
+		line("SUMMARY:%s", icalEscape.Replace(event.summary))
+		line("DESCRIPTION:%s", icalEscape.Replace(fmt.Sprintf("%s from %s to %s", event.summary, start.In(loc).Format("Mon 15:04"), end.In(loc).Format("Mon 15:04"))))
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,34 @@
+//go:build go1.23
+
+package smhi
+
+import (
+	"iter"
+	"time"
+)
+
+// Items returns an iterator over the forecast timeseries items.
+func (f *Forecast) Items() iter.Seq[TimeSeriesItem] {
+	return func(yield func(TimeSeriesItem) bool) {
+		for _, item := range f.TimeSeries {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// ItemsBetween returns an iterator over the forecast timeseries items valid
+// from start (inclusive) to end (exclusive).
+func (f *Forecast) ItemsBetween(start, end time.Time) iter.Seq[TimeSeriesItem] {
+	return func(yield func(TimeSeriesItem) bool) {
+		for _, item := range f.TimeSeries {
+			if item.ValidTime.Before(start) || !item.ValidTime.Before(end) {
+				continue
+			}
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
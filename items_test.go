@@ -0,0 +1,27 @@
+//go:build go1.23
+
+package smhi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemsBetween(t *testing.T) {
+	forecast := readForecast(t)
+
+	count := 0
+	for range forecast.Items() {
+		count++
+	}
+	require.Equal(t, len(forecast.TimeSeries), count)
+
+	start := time.Date(2024, 7, 14, 0, 0, 0, 0, time.UTC)
+	var temps []float64
+	for item := range forecast.ItemsBetween(start, start.Add(3*time.Hour)) {
+		temps = append(temps, item.Temperature())
+	}
+	require.Len(t, temps, 3)
+}
@@ -0,0 +1,77 @@
+package smhi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// parameterList is SMHI's parameter metadata response.
+type parameterList struct {
+	Parameter []struct {
+		Name      string
+		LongName  string
+		LevelType string
+		Level     int
+		Unit      string
+	}
+}
+
+func (c *Client) parametersURL() string {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	return base + "/api/category/pmp3g/version/2/parameter.json"
+}
+
+// GetParameterDescriptions requests SMHI's published parameter metadata,
+// e.g. to detect when ParameterDescriptions is outdated. The live metadata
+// takes precedence: only parameters SMHI lists are returned, with their
+// level, unit and description from SMHI. SMHI doesn't publish value ranges,
+// so ValueRange is taken from ParameterDescriptions where known. On error a
+// copy of ParameterDescriptions is returned along with the error.
+func (c *Client) GetParameterDescriptions(ctx context.Context) (map[string]ParameterDescription, error) {
+	descriptions, err := c.getParameterDescriptions(ctx)
+	if err != nil {
+		return maps.Clone(ParameterDescriptions), err
+	}
+	return descriptions, nil
+}
+
+func (c *Client) getParameterDescriptions(ctx context.Context) (map[string]ParameterDescription, error) {
+	buf, _, err := c.get(ctx, c.parametersURL())
+	if err != nil {
+		return nil, err
+	}
+
+	var list parameterList
+	if err := json.Unmarshal(buf, &list); err != nil {
+		return nil, fmt.Errorf("decode parameters: %w", err)
+	}
+	if len(list.Parameter) == 0 {
+		return nil, errors.New("no parameters in response")
+	}
+
+	descriptions := make(map[string]ParameterDescription, len(list.Parameter))
+	for _, p := range list.Parameter {
+		descriptions[p.Name] = ParameterDescription{
+			Name:        p.Name,
+			LevelType:   p.LevelType,
+			Level:       p.Level,
+			Unit:        p.Unit,
+			Description: p.LongName,
+			ValueRange:  ParameterDescriptions[p.Name].ValueRange,
+		}
+	}
+	return descriptions, nil
+}
+
+// GetParameterDescriptions requests SMHI's published parameter metadata. It
+// uses a zero value Client, see Client.GetParameterDescriptions.
+func GetParameterDescriptions() (map[string]ParameterDescription, error) {
+	var c Client
+	return c.GetParameterDescriptions(context.Background())
+}
@@ -0,0 +1,192 @@
+package smhi
+
+import (
+	"math"
+	"time"
+)
+
+// PrecipTypeChange is a change of precipitation form, e.g. from rain to snow.
+type PrecipTypeChange struct {
+	// Time is when the new precipitation form starts.
+	Time time.Time
+	From PrecipitationCategory
+	To   PrecipitationCategory
+}
+
+// PrecipTypeChanges returns the changes of precipitation category. Items
+// without precipitation are skipped so rain, a dry spell and then snow is
+// reported as a change from rain to snow. The times are in loc.
+func (f *Forecast) PrecipTypeChanges(loc *time.Location) []PrecipTypeChange {
+	var changes []PrecipTypeChange
+	var last PrecipitationCategory
+	for _, item := range f.TimeSeries {
+		c, ok := item.ActivePrecipitationCategory()
+		if !ok {
+			continue
+		}
+		if last != PrecipitationNone && c != last {
+			changes = append(changes, PrecipTypeChange{
+				Time: item.ValidTime.In(loc),
+				From: last,
+				To:   c,
+			})
+		}
+		last = c
+	}
+	return changes
+}
+
+// RainClass is a coarse precipitation intensity class.
+type RainClass int
+
+// Rain classes.
+const (
+	RainNone RainClass = iota
+	RainLight
+	RainModerate
+	RainHeavy
+)
+
+// Cutoffs in mm/h used by RainClass, following the American Meteorological
+// Society's definitions of light, moderate and heavy rain.
+const (
+	RainLightCutoff    = 0.1
+	RainModerateCutoff = 2.5
+	RainHeavyCutoff    = 7.6
+)
+
+// String returns the rain class as a human readable string.
+func (c RainClass) String() string {
+	switch c {
+	case RainLight:
+		return "light"
+	case RainModerate:
+		return "moderate"
+	case RainHeavy:
+		return "heavy"
+	}
+	return "none"
+}
+
+// RainClass classifies the mean precipitation intensity for this forecast
+// timeseries item by the cutoffs RainLightCutoff, RainModerateCutoff and
+// RainHeavyCutoff. If the mean is below RainLightCutoff but the maximum
+// intensity isn't, the item is RainLight since showers are possible. The
+// class doesn't depend on the weather symbol.
+func (i TimeSeriesItem) RainClass() RainClass {
+	_, pmean, pmax := i.PrecipitationBand()
+	switch {
+	case pmean >= RainHeavyCutoff:
+		return RainHeavy
+	case pmean >= RainModerateCutoff:
+		return RainModerate
+	case pmean >= RainLightCutoff, pmax >= RainLightCutoff:
+		return RainLight
+	}
+	return RainNone
+}
+
+// TimeUntilRain returns how long it is from now until the mean precipitation
+// intensity first exceeds threshold mm/h, or 0 if it already does. Returns
+// false if it doesn't rain during the rest of the forecast. The answer is no
+// more precise than the step of the item where the rain starts, i.e. 1 hour
+// for the first couple of days and up to 12 hours later on, so "rain in 45
+// minutes" means rain is forecast for the hour starting then.
+func (f *Forecast) TimeUntilRain(now time.Time, threshold float64) (time.Duration, bool) {
+	n := len(f.TimeSeries)
+	if n == 0 {
+		return 0, false
+	}
+	ok, start := f.WillRainAt(now, f.TimeSeries[n-1].ValidTime.Sub(now), threshold)
+	if !ok {
+		return 0, false
+	}
+	return start.Sub(now), true
+}
+
+// UmbrellaScore rates from 0 to 10 how much an umbrella is needed during
+// this forecast timeseries item. SMHI has no precipitation probability so the
+// maximum intensity stands in for the chance of showers:
+//
+//   - 2 points per mm/h of mean precipitation intensity (pmean), so moderate
+//     rain of 2.5 mm/h scores 5 and 5 mm/h or more scores 10.
+//   - 1 point per mm/h of maximum intensity (pmax), at most 3.
+//   - Half the score for snow, where an umbrella helps less.
+//   - 1 extra point for freezing rain and freezing drizzle.
+//
+// The score is rounded and clamped to 0-10.
+func (i TimeSeriesItem) UmbrellaScore() int {
+	score := 2*i.Float64(ParamMeanPrecipitation) + min(3, i.Float64(ParamMaxPrecipitation))
+	switch i.PrecipitationCategory() {
+	case PrecipitationSnow:
+		score /= 2
+	case PrecipitationFreezingRain, PrecipitationFreezingDrizzle:
+		score++
+	}
+	return int(math.Round(min(10, max(0, score))))
+}
+
+// PrecipPoint is the accumulated precipitation at a point in time.
+type PrecipPoint struct {
+	Time time.Time
+	// Total is the accumulated precipitation in mm since the start of the
+	// forecast.
+	Total float64
+}
+
+// CumulativePrecipitation returns the accumulated mean precipitation at each
+// valid time, starting at 0 for the first item. Each item's intensity applies
+// until the next item. The times are in loc.
+func (f *Forecast) CumulativePrecipitation(loc *time.Location) []PrecipPoint {
+	points := make([]PrecipPoint, len(f.TimeSeries))
+	var total float64
+	for idx, item := range f.TimeSeries {
+		points[idx] = PrecipPoint{Time: item.ValidTime.In(loc), Total: total}
+		total += item.Float64(ParamMeanPrecipitation) * f.stepDuration(idx).Hours()
+	}
+	return points
+}
+
+// WillRain returns whether the mean precipitation intensity exceeds threshold
+// mm/h at any time from now until now+within, and when it first does. If it
+// is already raining the time is now.
+func (f *Forecast) WillRain(within time.Duration, threshold float64) (bool, time.Time) {
+	return f.WillRainAt(time.Now(), within, threshold)
+}
+
+// WillRainAt is like WillRain but relative to now instead of the current
+// time.
+func (f *Forecast) WillRainAt(now time.Time, within time.Duration, threshold float64) (bool, time.Time) {
+	end := now.Add(within)
+	for idx, item := range f.TimeSeries {
+		inWindow := f.overlap(idx, now, end) > 0 || (!item.ValidTime.Before(now) && !item.ValidTime.After(end))
+		if inWindow && item.Float64(ParamMeanPrecipitation) > threshold {
+			if item.ValidTime.Before(now) {
+				return true, now
+			}
+			return true, item.ValidTime
+		}
+	}
+	return false, time.Time{}
+}
+
+// SnowRatio is the assumed depth of fresh snow per depth of liquid water,
+// i.e. 1 mm of precipitation gives 1 cm of snow.
+const SnowRatio = 10
+
+// SnowAccumulation returns the expected depth of fresh snow in cm from start
+// to end. Only the frozen part of the mean precipitation (see the spp
+// parameter) is counted and it is converted with SnowRatio. Melting,
+// compaction and drifting are not taken into account so the depth on the
+// ground is usually less.
+func (f *Forecast) SnowAccumulation(start, end time.Time) float64 {
+	var mm float64
+	for idx, item := range f.TimeSeries {
+		frozen := item.Float64(ParamFrozenPrecipitation)
+		if frozen <= 0 {
+			continue
+		}
+		mm += frozen / 100 * item.Float64(ParamMeanPrecipitation) * f.overlap(idx, start, end).Hours()
+	}
+	return mm * SnowRatio / 10
+}
@@ -0,0 +1,54 @@
+package smhi
+
+import "time"
+
+// PressureTrendThreshold is the change in air pressure in hPa that
+// PressureTrend considers rising or falling. Smaller changes are steady.
+const PressureTrendThreshold = 1.0
+
+// PressureTrend is a barometric tendency.
+type PressureTrend int
+
+// Pressure trends.
+const (
+	PressureUnknown PressureTrend = iota
+	PressureSteady
+	PressureRising
+	PressureFalling
+)
+
+// String returns the pressure trend as a human readable string.
+func (p PressureTrend) String() string {
+	switch p {
+	case PressureSteady:
+		return "steady"
+	case PressureRising:
+		return "rising"
+	case PressureFalling:
+		return "falling"
+	}
+	return "unknown"
+}
+
+// PressureTrend compares the air pressure at time at with the pressure at
+// at+window. Changes of at least PressureTrendThreshold hPa are rising or
+// falling, anything less is steady. Returns PressureUnknown if the forecast
+// doesn't cover both times.
+func (f *Forecast) PressureTrend(at time.Time, window time.Duration) PressureTrend {
+	from, ok := f.InterpolateFloat64(ParamPressure, at)
+	if !ok {
+		return PressureUnknown
+	}
+	to, ok := f.InterpolateFloat64(ParamPressure, at.Add(window))
+	if !ok {
+		return PressureUnknown
+	}
+
+	switch diff := to - from; {
+	case diff >= PressureTrendThreshold:
+		return PressureRising
+	case diff <= -PressureTrendThreshold:
+		return PressureFalling
+	}
+	return PressureSteady
+}
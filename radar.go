@@ -0,0 +1,88 @@
+package smhi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultRadarBaseURL is the base URL of the SMHI radar open data API.
+const DefaultRadarBaseURL = "https://opendata-download-radar.smhi.se"
+
+// RadarImage is a radar composite of precipitation over Sweden.
+type RadarImage struct {
+	Key string
+	// Valid is the time of the radar scan.
+	Valid time.Time
+	// URLs are the download links of the image by format, e.g. "png" and
+	// "tif".
+	URLs map[string]string
+}
+
+// radarIndex is SMHI's response describing a radar composite.
+type radarIndex struct {
+	Key     string
+	Valid   string
+	Formats []struct {
+		Key  string
+		Link string
+	}
+}
+
+// parseRadarTime parses the valid time of a radar composite, which SMHI
+// gives either in RFC 3339 or as "2006-01-02 15:04" in UTC.
+func parseRadarTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02 15:04", value)
+}
+
+func (c *Client) radarURL() string {
+	base := c.RadarBaseURL
+	if base == "" {
+		base = DefaultRadarBaseURL
+	}
+	return base + "/api/version/latest/area/sweden/product/comp/latest"
+}
+
+// GetLatestRadar requests the index of the most recent radar composite and
+// returns its time and download links. The image itself is not downloaded.
+func (c *Client) GetLatestRadar(ctx context.Context) (*RadarImage, error) {
+	buf, _, err := c.get(ctx, c.radarURL())
+	if err != nil {
+		return nil, err
+	}
+
+	var index radarIndex
+	if err := json.Unmarshal(buf, &index); err != nil {
+		return nil, fmt.Errorf("decode radar index: %w", err)
+	}
+	if len(index.Formats) == 0 {
+		return nil, errors.New("no formats in radar index")
+	}
+
+	valid, err := parseRadarTime(index.Valid)
+	if err != nil {
+		return nil, fmt.Errorf("radar valid time: %w", err)
+	}
+
+	image := &RadarImage{
+		Key:   index.Key,
+		Valid: valid,
+		URLs:  make(map[string]string, len(index.Formats)),
+	}
+	for _, f := range index.Formats {
+		image.URLs[f.Key] = f.Link
+	}
+	return image, nil
+}
+
+// GetLatestRadar requests the index of the most recent radar composite. It
+// uses a zero value Client, see Client.GetLatestRadar.
+func GetLatestRadar() (*RadarImage, error) {
+	var c Client
+	return c.GetLatestRadar(context.Background())
+}
@@ -1,168 +1,200 @@
 package smhi
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
+// Parameter names as used by SMHI.
+const (
+	ParamPressure              = "msl"
+	ParamTemperature           = "t"
+	ParamVisibility            = "vis"
+	ParamWindDirection         = "wd"
+	ParamWindSpeed             = "ws"
+	ParamHumidity              = "r"
+	ParamThunderProbability    = "tstm"
+	ParamTotalCloudCover       = "tcc_mean"
+	ParamLowCloudCover         = "lcc_mean"
+	ParamMediumCloudCover      = "mcc_mean"
+	ParamHighCloudCover        = "hcc_mean"
+	ParamGust                  = "gust"
+	ParamMinPrecipitation      = "pmin"
+	ParamMaxPrecipitation      = "pmax"
+	ParamFrozenPrecipitation   = "spp"
+	ParamPrecipitationCategory = "pcat"
+	ParamMeanPrecipitation     = "pmean"
+	ParamMedianPrecipitation   = "pmedian"
+	ParamWeatherSymbol         = "Wsymb2"
+)
+
 // ParameterDescriptions describe the forecast timeseries item parameters. See
 // https://opendata.smhi.se/apidocs/metfcst/parameters.html
 var ParameterDescriptions = map[string]ParameterDescription{
-	"msl": {
-		Name:        "msl",
+	ParamPressure: {
+		Name:        ParamPressure,
 		LevelType:   "hmsl",
 		Level:       0,
 		Unit:        "hPa",
 		Description: "Air pressure",
 		ValueRange:  "Decimal number, one decimal",
 	},
-	"t": {
-		Name:        "t",
+	ParamTemperature: {
+		Name:        ParamTemperature,
 		LevelType:   "hl",
 		Level:       2,
 		Unit:        "C",
 		Description: "Air temperature",
 		ValueRange:  "Decimal number, one decimal",
 	},
-	"vis": {
-		Name:        "vis",
+	ParamVisibility: {
+		Name:        ParamVisibility,
 		LevelType:   "hl",
 		Level:       2,
 		Unit:        "km",
 		Description: "Horizontal visibility",
 		ValueRange:  "Decimal number, one decimal",
 	},
-	"wd": {
-		Name:        "wd",
+	ParamWindDirection: {
+		Name:        ParamWindDirection,
 		LevelType:   "hl",
 		Level:       10,
 		Unit:        "degree",
 		Description: "Wind direction",
 		ValueRange:  "Integer",
 	},
-	"ws": {
-		Name:        "ws",
+	ParamWindSpeed: {
+		Name:        ParamWindSpeed,
 		LevelType:   "hl",
 		Level:       10,
 		Unit:        "m/s",
 		Description: "Wind speed",
 		ValueRange:  "Decimal number, one decimal",
 	},
-	"r": {
-		Name:        "r",
+	ParamHumidity: {
+		Name:        ParamHumidity,
 		LevelType:   "hl",
 		Level:       2,
 		Unit:        "%",
 		Description: "Relative humidity",
 		ValueRange:  "Integer, 0-100",
 	},
-	"tstm": {
-		Name:        "tstm",
+	ParamThunderProbability: {
+		Name:        ParamThunderProbability,
 		LevelType:   "hl",
 		Level:       0,
 		Unit:        "%",
 		Description: "Thunder probability",
 		ValueRange:  "Integer, 0-100",
 	},
-	"tcc_mean": {
-		Name:        "tcc_mean",
+	ParamTotalCloudCover: {
+		Name:        ParamTotalCloudCover,
 		LevelType:   "hl",
 		Level:       0,
 		Unit:        "octas",
 		Description: "Mean value of total cloud cover",
 		ValueRange:  "Integer, 0-8",
 	},
-	"lcc_mean": {
-		Name:        "lcc_mean",
+	ParamLowCloudCover: {
+		Name:        ParamLowCloudCover,
 		LevelType:   "hl",
 		Level:       0,
 		Unit:        "octas",
 		Description: "Mean value of low level cloud cover",
 		ValueRange:  "Integer, 0-8",
 	},
-	"mcc_mean": {
-		Name:        "mcc_mean",
+	ParamMediumCloudCover: {
+		Name:        ParamMediumCloudCover,
 		LevelType:   "hl",
 		Level:       0,
 		Unit:        "octas",
 		Description: "Mean value of medium level cloud cover",
 		ValueRange:  "Integer, 0-8",
 	},
-	"hcc_mean": {
-		Name:        "hcc_mean",
+	ParamHighCloudCover: {
+		Name:        ParamHighCloudCover,
 		LevelType:   "hl",
 		Level:       0,
 		Unit:        "octas",
 		Description: "Mean value of high level cloud cover",
 		ValueRange:  "Integer, 0-8",
 	},
-	"gust": {
-		Name:        "gust",
+	ParamGust: {
+		Name:        ParamGust,
 		LevelType:   "hl",
 		Level:       10,
 		Unit:        "m/s",
 		Description: "Wind gust speed",
 		ValueRange:  "Decimal number, one decimal",
 	},
-	"pmin": {
-		Name:        "pmin",
+	ParamMinPrecipitation: {
+		Name:        ParamMinPrecipitation,
 		LevelType:   "hl",
 		Level:       0,
 		Unit:        "mm/h",
 		Description: "Minimum precipitation intensity",
 		ValueRange:  "Decimal number, one decimal",
 	},
-	"pmax": {
-		Name:        "pmax",
+	ParamMaxPrecipitation: {
+		Name:        ParamMaxPrecipitation,
 		LevelType:   "hl",
 		Level:       0,
 		Unit:        "mm/h",
 		Description: "Maximum precipitation intensity",
 		ValueRange:  "Decimal number, one decimal",
 	},
-	"spp": {
-		Name:        "spp",
+	ParamFrozenPrecipitation: {
+		Name:        ParamFrozenPrecipitation,
 		LevelType:   "hl",
 		Level:       0,
 		Unit:        "%",
 		Description: "Percent of precipitation in frozen form",
 		ValueRange:  "Integer, -9 or 0-100",
 	},
-	"pcat": {
-		Name:        "pcat",
+	ParamPrecipitationCategory: {
+		Name:        ParamPrecipitationCategory,
 		LevelType:   "hl",
 		Level:       0,
 		Unit:        "category",
 		Description: "Precipitation category",
 		ValueRange:  "Integer, 0-6",
 	},
-	"pmean": {
-		Name:        "pmean",
+	ParamMeanPrecipitation: {
+		Name:        ParamMeanPrecipitation,
 		LevelType:   "hl",
 		Level:       0,
 		Unit:        "mm/h",
 		Description: "Mean precipitation intensity",
 		ValueRange:  "Decimal number, one decimal",
 	},
-	"pmedian": {
-		Name:        "pmedian",
+	ParamMedianPrecipitation: {
+		Name:        ParamMedianPrecipitation,
 		LevelType:   "hl",
 		Level:       0,
 		Unit:        "mm/h",
 		Description: "Median precipitation intensity",
 		ValueRange:  "Decimal number, one decimal",
 	},
-	"wsymb2": {
-		Name:        "wsymb2",
-		LevelType:   "hl",
-		Level:       0,
-		Unit:        "code",
-		Description: "Weather symbol",
-		ValueRange:  "Integer, 1-27",
-	},
+	ParamWeatherSymbol: weatherSymbolDescription,
+	// "wsymb2" was the key of the weather symbol before it was corrected to
+	// SMHI's name, kept so existing lookups still work.
+	"wsymb2": weatherSymbolDescription,
+}
+
+var weatherSymbolDescription = ParameterDescription{
+	Name:        ParamWeatherSymbol,
+	LevelType:   "hl",
+	Level:       0,
+	Unit:        "code",
+	Description: "Weather symbol",
+	ValueRange:  "Integer, 1-27",
 }
 
 // ParameterDescription describes a forecast timeseries item.
@@ -207,6 +239,27 @@ var WeatherSymbols = []WeatherSymbol{
 	{27, "Heavy snowfall", "\U0001f328", 1},         // 🌨
 }
 
+var customWeatherSymbols atomic.Pointer[[]WeatherSymbol]
+
+// SetWeatherSymbols replaces the table used by TimeSeriesItem.WeatherSymbol,
+// e.g. to use a custom icon set or translated meanings. The table must be
+// indexed by value like WeatherSymbols. Passing nil restores WeatherSymbols.
+func SetWeatherSymbols(table []WeatherSymbol) {
+	if table == nil {
+		customWeatherSymbols.Store(nil)
+		return
+	}
+	customWeatherSymbols.Store(&table)
+}
+
+// AllWeatherSymbols returns the real weather symbols 1-27 in order, i.e.
+// WeatherSymbols without the placeholder at index 0.
+func AllWeatherSymbols() []WeatherSymbol {
+	symbols := make([]WeatherSymbol, len(WeatherSymbols)-1)
+	copy(symbols, WeatherSymbols[1:])
+	return symbols
+}
+
 // WeatherSymbol describe a forecast timeseries item weather symbol.
 type WeatherSymbol struct {
 	Value        int
@@ -215,6 +268,12 @@ type WeatherSymbol struct {
 	UnicodeWidth int
 }
 
+// Emoji returns the plain Unicode character for the symbol without any
+// padding. Use this for HTML, JSON etc.
+func (s WeatherSymbol) Emoji() string {
+	return s.Unicode
+}
+
 // FixedWidth returns a string representationt that is suitable to print in a
 // terminal.
 func (s WeatherSymbol) FixedWidth() string {
@@ -224,6 +283,76 @@ func (s WeatherSymbol) FixedWidth() string {
 	return s.Unicode + "\u200b"
 }
 
+// Intensity is the precipitation intensity of a weather symbol.
+type Intensity int
+
+// Precipitation intensities.
+const (
+	IntensityNone Intensity = iota
+	IntensityLight
+	IntensityModerate
+	IntensityHeavy
+)
+
+// String returns the intensity as a human readable string.
+func (l Intensity) String() string {
+	switch l {
+	case IntensityLight:
+		return "light"
+	case IntensityModerate:
+		return "moderate"
+	case IntensityHeavy:
+		return "heavy"
+	}
+	return "none"
+}
+
+// IntensityLevel returns the precipitation intensity of the symbol. Symbols
+// without precipitation (including thunder) return IntensityNone.
+func (s WeatherSymbol) IntensityLevel() Intensity {
+	switch s.Value {
+	case 8, 12, 15, 18, 22, 25:
+		return IntensityLight
+	case 9, 13, 16, 19, 23, 26:
+		return IntensityModerate
+	case 10, 14, 17, 20, 24, 27:
+		return IntensityHeavy
+	}
+	return IntensityNone
+}
+
+// IsPrecipitation returns whether the symbol is a precipitation or thunder
+// symbol, i.e. not clear, cloudy or fog.
+func (s WeatherSymbol) IsPrecipitation() bool {
+	return s.Value >= 8 && s.Value <= 27
+}
+
+// symbolSeverity ranks the weather symbols by value. Cloudiness ranks lowest,
+// then fog, then precipitation by intensity (showers before continuous,
+// rain before sleet before snow) and thunder highest.
+var symbolSeverity = [...]int{
+	// No weather, clear sky .. fog
+	0, 1, 2, 3, 4, 5, 6, 7,
+	// Rain showers, thunderstorm
+	8, 14, 20, 27,
+	// Sleet showers, snow showers
+	10, 16, 22, 12, 18, 24,
+	// Rain, thunder
+	9, 15, 21, 26,
+	// Sleet, snowfall
+	11, 17, 23, 13, 19, 25,
+}
+
+// Severity returns how significant the weather symbol is, from 0 (no
+// weather) and 1 (clear sky) to 27 (thunderstorm). Use it to pick the most
+// significant symbol among several.
+func (s WeatherSymbol) Severity() int {
+	if s.Value >= 0 && s.Value < len(symbolSeverity) {
+		return symbolSeverity[s.Value]
+	}
+	return 0
+}
+
 // Forecast represents a 10 day forecast. See
 // https://opendata.smhi.se/apidocs/metfcst/get-forecast.html
 type Forecast struct {
@@ -233,6 +362,69 @@ type Forecast struct {
 	TimeSeries    []TimeSeriesItem
 }
 
+// Clone returns a deep copy of the forecast. The copy shares no slices with
+// the original so either can be modified without affecting the other.
+func (f *Forecast) Clone() *Forecast {
+	clone := *f
+	clone.Geometry.Coordinates = slices.Clone(f.Geometry.Coordinates)
+	clone.TimeSeries = make([]TimeSeriesItem, len(f.TimeSeries))
+	for idx, item := range f.TimeSeries {
+		clone.TimeSeries[idx] = item.Clone()
+	}
+	return &clone
+}
+
+// ReliableHorizon is how far beyond the approved time a forecast is
+// considered reliable. Beyond about 5 days forecast skill drops sharply.
+const ReliableHorizon = 5 * 24 * time.Hour
+
+// ReliableUntil returns the time until which the forecast is considered
+// reliable, i.e. ApprovedTime plus ReliableHorizon.
+func (f *Forecast) ReliableUntil() time.Time {
+	return f.ApprovedTime.Add(ReliableHorizon)
+}
+
+// ErrEmptyForecast is returned when a forecast has no timeseries items.
+var ErrEmptyForecast = errors.New("forecast has no timeseries items")
+
+// nearestIndex returns the index of the timeseries item closest in time to t,
+// or -1 if the forecast is empty.
+func (f *Forecast) nearestIndex(t time.Time) int {
+	best := -1
+	var bestDiff time.Duration
+	for idx, item := range f.TimeSeries {
+		diff := item.ValidTime.Sub(t).Abs()
+		if best < 0 || diff < bestDiff {
+			best = idx
+			bestDiff = diff
+		}
+	}
+	return best
+}
+
+// Current returns the timeseries item closest in time to now. Returns false
+// if the forecast is empty.
+func (f *Forecast) Current(now time.Time) (TimeSeriesItem, bool) {
+	idx := f.nearestIndex(now)
+	if idx < 0 {
+		return TimeSeriesItem{}, false
+	}
+	return f.TimeSeries[idx], true
+}
+
+// Around returns the timeseries item closest in time to t and up to n items
+// before and after it. Fewer items are returned near the ends of the
+// forecast and none if the forecast is empty.
+func (f *Forecast) Around(t time.Time, n int) []TimeSeriesItem {
+	idx := f.nearestIndex(t)
+	if idx < 0 {
+		return nil
+	}
+	from := max(0, idx-n)
+	to := min(len(f.TimeSeries), idx+n+1)
+	return slices.Clone(f.TimeSeries[from:to])
+}
+
 // Geometry describes the forecast area.
 type Geometry struct {
 	Type        string
@@ -248,6 +440,41 @@ type TimeSeriesItem struct {
 	Parameters []Parameter
 }
 
+// Clone returns a deep copy of the timeseries item.
+func (i TimeSeriesItem) Clone() TimeSeriesItem {
+	clone := i
+	clone.Parameters = make([]Parameter, len(i.Parameters))
+	for idx, p := range i.Parameters {
+		p.Values = slices.Clone(p.Values)
+		clone.Parameters[idx] = p
+	}
+	return clone
+}
+
+// Lookup returns the parameter by the given name as a float64. Returns false
+// if the item doesn't have the parameter.
+func (i TimeSeriesItem) Lookup(name string) (float64, bool) {
+	for _, p := range i.Parameters {
+		if p.Name == name && len(p.Values) > 0 {
+			return p.Values[0], true
+		}
+	}
+	return 0, false
+}
+
+// AsMap returns the first value of each parameter keyed by parameter name,
+// e.g. for templates or generic output. Parameters without values are
+// omitted.
+func (i TimeSeriesItem) AsMap() map[string]float64 {
+	m := make(map[string]float64, len(i.Parameters))
+	for _, p := range i.Parameters {
+		if len(p.Values) > 0 {
+			m[p.Name] = p.Values[0]
+		}
+	}
+	return m
+}
+
 // Float64 returns the parameter by the given name as a float64.
 func (i TimeSeriesItem) Float64(name string) float64 {
 	for _, p := range i.Parameters {
@@ -270,28 +497,237 @@ func (i TimeSeriesItem) Int(name string) int {
 
 // Temperature returns the temperature for this forecast timeseries item.
 func (i TimeSeriesItem) Temperature() float64 {
-	return i.Float64("t")
+	return i.Float64(ParamTemperature)
+}
+
+// TemperatureK returns the temperature in Kelvin for this forecast timeseries
+// item.
+func (i TimeSeriesItem) TemperatureK() float64 {
+	return i.Temperature() + 273.15
+}
+
+// StandardLapseRate is the temperature decrease in °C per km of altitude in
+// the standard atmosphere.
+const StandardLapseRate = 6.5
+
+// FreezingLevel estimates the altitude in metres above the forecast point
+// where the temperature reaches 0°C, assuming the temperature decreases with
+// StandardLapseRate from the surface temperature. SMHI only provides surface
+// values so this is a rough estimate; actual lapse rates vary a lot, e.g.
+// with inversions. Returns 0 if the surface temperature is at or below 0°C.
+func (i TimeSeriesItem) FreezingLevel() float64 {
+	return max(0, i.Temperature()/StandardLapseRate*1000)
+}
+
+// Pressure returns the air pressure at mean sea level in hPa for this
+// forecast timeseries item.
+func (i TimeSeriesItem) Pressure() float64 {
+	return i.Float64(ParamPressure)
 }
 
 // MaxPrecipitation returns the max precipitation for this forecast timeseries item.
 func (i TimeSeriesItem) MaxPrecipitation() float64 {
-	return i.Float64("pmax")
+	return i.Float64(ParamMaxPrecipitation)
+}
+
+// Visibility returns the horizontal visibility in km for this forecast
+// timeseries item.
+func (i TimeSeriesItem) Visibility() float64 {
+	return i.Float64(ParamVisibility)
+}
+
+// FogVisibility is the visibility in km below which IsFoggy reports fog. This
+// is the meteorological definition of fog.
+const FogVisibility = 1.0
+
+// IsFoggy returns true if the visibility is below FogVisibility or the weather
+// symbol is Fog.
+func (i TimeSeriesItem) IsFoggy() bool {
+	if vis, ok := i.Lookup(ParamVisibility); ok && vis < FogVisibility {
+		return true
+	}
+	return i.WeatherSymbol().Value == 7
+}
+
+// PrecipitationBand returns the minimum, mean and maximum precipitation
+// intensity in mm/h for this forecast timeseries item.
+func (i TimeSeriesItem) PrecipitationBand() (min, mean, max float64) {
+	for _, p := range i.Parameters {
+		if len(p.Values) == 0 {
+			continue
+		}
+		switch p.Name {
+		case ParamMinPrecipitation:
+			min = p.Values[0]
+		case ParamMeanPrecipitation:
+			mean = p.Values[0]
+		case ParamMaxPrecipitation:
+			max = p.Values[0]
+		}
+	}
+	return min, mean, max
+}
+
+// ThunderProbability returns the thunder probability in percent for this
+// forecast timeseries item.
+func (i TimeSeriesItem) ThunderProbability() int {
+	return i.Int(ParamThunderProbability)
 }
 
 // WindSpeed returns the wind speed for this forecast timeseries item.
 func (i TimeSeriesItem) WindSpeed() float64 {
-	return i.Float64("ws")
+	return i.Float64(ParamWindSpeed)
 }
 
 // WeatherSymbol returns the weather symbol for this forecast timeseries item.
+// The symbol is looked up in the table set by SetWeatherSymbols, by default
+// WeatherSymbols.
 func (i TimeSeriesItem) WeatherSymbol() WeatherSymbol {
-	idx := i.Int("Wsymb2")
-	if idx >= 1 && idx < len(WeatherSymbols) {
-		return WeatherSymbols[idx]
+	table := WeatherSymbols
+	if custom := customWeatherSymbols.Load(); custom != nil {
+		table = *custom
+	}
+	return i.WeatherSymbolFrom(table)
+}
+
+// WeatherSymbolFrom returns the weather symbol for this forecast timeseries
+// item from a custom table indexed by value like WeatherSymbols.
+func (i TimeSeriesItem) WeatherSymbolFrom(table []WeatherSymbol) WeatherSymbol {
+	idx := i.Int(ParamWeatherSymbol)
+	if idx >= 1 && idx < len(table) {
+		return table[idx]
 	}
 	return WeatherSymbol{}
 }
 
+// Octas is cloud cover in eighths of the sky, 0 (clear) to 8 (overcast).
+type Octas int
+
+// Percent returns the cloud cover as a percentage 0-100.
+func (o Octas) Percent() float64 {
+	return float64(o) * 100 / 8
+}
+
+// String returns the cloud cover as e.g. "5/8".
+func (o Octas) String() string {
+	return fmt.Sprintf("%d/8", int(o))
+}
+
+// CloudCoverText describes cloud cover in octas in words, e.g. "partly
+// cloudy". Returns "unknown" for values outside of 0-8.
+func CloudCoverText(octas int) string {
+	switch {
+	case octas == 0:
+		return "clear"
+	case octas >= 1 && octas <= 2:
+		return "mostly clear"
+	case octas >= 3 && octas <= 5:
+		return "partly cloudy"
+	case octas >= 6 && octas <= 7:
+		return "mostly cloudy"
+	case octas == 8:
+		return "overcast"
+	}
+	return "unknown"
+}
+
+// CloudCoverText describes the total cloud cover for this forecast timeseries
+// item in words. See CloudCoverText.
+func (i TimeSeriesItem) CloudCoverText() string {
+	return CloudCoverText(int(i.TotalCloudCover()))
+}
+
+// TotalCloudCover returns the mean total cloud cover for this forecast
+// timeseries item.
+func (i TimeSeriesItem) TotalCloudCover() Octas {
+	return Octas(i.Int(ParamTotalCloudCover))
+}
+
+// LowCloudCover returns the mean low level cloud cover for this forecast
+// timeseries item.
+func (i TimeSeriesItem) LowCloudCover() Octas {
+	return Octas(i.Int(ParamLowCloudCover))
+}
+
+// MediumCloudCover returns the mean medium level cloud cover for this
+// forecast timeseries item.
+func (i TimeSeriesItem) MediumCloudCover() Octas {
+	return Octas(i.Int(ParamMediumCloudCover))
+}
+
+// HighCloudCover returns the mean high level cloud cover for this forecast
+// timeseries item.
+func (i TimeSeriesItem) HighCloudCover() Octas {
+	return Octas(i.Int(ParamHighCloudCover))
+}
+
+// PrecipitationCategory is the form of precipitation (the pcat parameter).
+type PrecipitationCategory int
+
+// Precipitation categories.
+const (
+	PrecipitationNone PrecipitationCategory = iota
+	PrecipitationSnow
+	PrecipitationSnowAndRain
+	PrecipitationRain
+	PrecipitationDrizzle
+	PrecipitationFreezingRain
+	PrecipitationFreezingDrizzle
+)
+
+// String returns the precipitation category as a human readable string.
+func (c PrecipitationCategory) String() string {
+	switch c {
+	case PrecipitationNone:
+		return "No precipitation"
+	case PrecipitationSnow:
+		return "Snow"
+	case PrecipitationSnowAndRain:
+		return "Snow and rain"
+	case PrecipitationRain:
+		return "Rain"
+	case PrecipitationDrizzle:
+		return "Drizzle"
+	case PrecipitationFreezingRain:
+		return "Freezing rain"
+	case PrecipitationFreezingDrizzle:
+		return "Freezing drizzle"
+	}
+	return fmt.Sprintf("PrecipitationCategory(%d)", int(c))
+}
+
+// PrecipitationCategory returns the precipitation category for this forecast
+// timeseries item.
+func (i TimeSeriesItem) PrecipitationCategory() PrecipitationCategory {
+	return PrecipitationCategory(i.Int(ParamPrecipitationCategory))
+}
+
+// ActivePrecipitationCategory returns the precipitation category for this
+// forecast timeseries item. Returns false if there is no precipitation.
+func (i TimeSeriesItem) ActivePrecipitationCategory() (PrecipitationCategory, bool) {
+	c := i.PrecipitationCategory()
+	return c, c != PrecipitationNone
+}
+
+// Report returns the parameters of this forecast timeseries item, one per
+// line, with the description and unit from ParameterDescriptions, e.g.
+// "Air temperature (t): 18.6 C".
+func (i TimeSeriesItem) Report() string {
+	var b strings.Builder
+	for _, p := range i.Parameters {
+		if len(p.Values) == 0 {
+			continue
+		}
+		desc, ok := ParameterDescriptions[p.Name]
+		if !ok {
+			fmt.Fprintln(&b, strings.TrimSpace(fmt.Sprintf("%s: %v %s", p.Name, p.Values[0], p.Unit)))
+			continue
+		}
+		fmt.Fprintf(&b, "%s (%s): %v %s\n", desc.Description, p.Name, p.Values[0], desc.Unit)
+	}
+	return b.String()
+}
+
 // Parameter is a forecast timeseries item paratemter e.g. temperature.
 type Parameter struct {
 	Name      string
@@ -302,27 +738,159 @@ type Parameter struct {
 }
 
 // GetForecast requests the 10 day forecast for a longitude/latitude coordinate.
+// It uses a zero value Client.
 func GetForecast(lon, lat float64) (*Forecast, error) {
-	resp, err := http.Get(fmt.Sprintf("https://opendata-download-metfcst.smhi.se/api/category/pmp3g/version/2/geotype/point/lon/%f/lat/%f/data.json", lon, lat))
+	return GetForecastContext(context.Background(), lon, lat)
+}
+
+// GetForecastContext is like GetForecast but the request is made with ctx,
+// so it can be cancelled and carries any values used by e.g. tracing
+// transports.
+func GetForecastContext(ctx context.Context, lon, lat float64) (*Forecast, error) {
+	var c Client
+	return c.GetForecast(ctx, lon, lat)
+}
+
+// GetCurrent requests the forecast for a longitude/latitude coordinate and
+// returns the current conditions.
+func GetCurrent(lon, lat float64) (TimeSeriesItem, error) {
+	forecast, err := GetForecast(lon, lat)
 	if err != nil {
-		return nil, err
+		return TimeSeriesItem{}, err
 	}
 
-	defer resp.Body.Close()
+	item, ok := forecast.Current(time.Now())
+	if !ok {
+		return TimeSeriesItem{}, ErrEmptyForecast
+	}
 
-	buf, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	return item, nil
+}
+
+// ParseError is returned when a forecast can't be decoded.
+type ParseError struct {
+	// Offset is the byte offset in the input where decoding failed, or -1
+	// if it isn't known.
+	Offset int64
+	// Field is the path or name of the field that failed to decode, if
+	// known.
+	Field string
+	// Context is a short excerpt of the input surrounding Offset.
+	Context string
+	Err     error
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	b.WriteString("parse error")
+	if e.Offset >= 0 {
+		fmt.Fprintf(&b, " at offset %d", e.Offset)
+	}
+	if e.Field != "" {
+		fmt.Fprintf(&b, " in field %s", e.Field)
+	}
+	if e.Context != "" {
+		fmt.Fprintf(&b, " near %q", e.Context)
+	}
+	fmt.Fprintf(&b, ": %v", e.Err)
+	return b.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parseErrorContext is the number of bytes to include on each side of the
+// offset in ParseError.Context.
+const parseErrorContext = 20
+
+func newParseError(buf []byte, err error) error {
+	perr := &ParseError{Offset: -1, Err: err}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+	switch {
+	case errors.As(err, &syntaxErr):
+		perr.Offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		perr.Offset = typeErr.Offset
+		perr.Field = typeErr.Field
+	case errors.As(err, &timeErr):
+		// time.Time doesn't report where it failed, so locate the first
+		// occurrence of the bad value instead.
+		if idx := bytes.Index(buf, []byte(`"`+timeErr.Value+`"`)); idx >= 0 {
+			perr.Offset = int64(idx)
+			perr.Field = jsonKeyBefore(buf, idx)
+		}
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status is not ok: %s", buf)
+	if perr.Offset >= 0 {
+		start := max(perr.Offset-parseErrorContext, 0)
+		end := min(perr.Offset+parseErrorContext, int64(len(buf)))
+		perr.Context = string(buf[start:end])
 	}
 
+	return perr
+}
+
+// jsonKeyBefore returns the object key of the value starting at offset, or
+// "" if the value isn't preceded by a key.
+func jsonKeyBefore(buf []byte, offset int) string {
+	prefix := bytes.TrimRight(buf[:offset], " \t\r\n")
+	prefix, ok := bytes.CutSuffix(prefix, []byte(":"))
+	if !ok {
+		return ""
+	}
+	prefix, ok = bytes.CutSuffix(bytes.TrimRight(prefix, " \t\r\n"), []byte(`"`))
+	if !ok {
+		return ""
+	}
+	start := bytes.LastIndexByte(prefix, '"')
+	if start < 0 {
+		return ""
+	}
+	return string(prefix[start+1:])
+}
+
+// ParseForecast decodes a forecast in SMHI's JSON format. Decoding failures
+// are returned as *ParseError.
+func ParseForecast(buf []byte) (*Forecast, error) {
 	var forecast Forecast
 	if err := json.Unmarshal(buf, &forecast); err != nil {
-		return nil, err
+		return nil, newParseError(buf, err)
 	}
 
 	return &forecast, nil
 }
+
+// ParseForecastInto is like ParseForecast but decodes into dst, reusing the
+// memory of its timeseries, parameters and values to reduce allocations
+// when the same Forecast is refreshed repeatedly. dst must not be used
+// concurrently, and slices previously taken from it are overwritten. Fields
+// missing in buf are zero rather than left over from the previous forecast,
+// except that a missing parameters or values array decodes to an empty
+// rather than a nil slice. On error dst is partly overwritten and should be
+// discarded.
+func ParseForecastInto(buf []byte, dst *Forecast) error {
+	// encoding/json decodes into the existing elements of a slice's backing
+	// array, so clear them to not leak fields missing in buf.
+	items := dst.TimeSeries[:cap(dst.TimeSeries)]
+	for idx := range items {
+		params := items[idx].Parameters[:cap(items[idx].Parameters)]
+		for j := range params {
+			params[j] = Parameter{Values: params[j].Values[:0]}
+		}
+		items[idx] = TimeSeriesItem{Parameters: params[:0]}
+	}
+
+	*dst = Forecast{
+		Geometry:   Geometry{Coordinates: dst.Geometry.Coordinates[:0]},
+		TimeSeries: dst.TimeSeries[:0],
+	}
+	if err := json.Unmarshal(buf, dst); err != nil {
+		return newParseError(buf, err)
+	}
+
+	return nil
+}
@@ -1,14 +1,47 @@
 package smhi_test
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tomyl/smhi"
 )
 
+func readForecast(t *testing.T) *smhi.Forecast {
+	t.Helper()
+	buf, err := os.ReadFile("testdata/data.json")
+	require.Nil(t, err)
+	forecast, err := smhi.ParseForecast(buf)
+	require.Nil(t, err)
+	return forecast
+}
+
+// newItem returns a timeseries item with the given name/value parameter pairs.
+func newItem(validTime time.Time, params ...any) smhi.TimeSeriesItem {
+	item := smhi.TimeSeriesItem{ValidTime: validTime}
+	for i := 0; i+1 < len(params); i += 2 {
+		var value float64
+		switch v := params[i+1].(type) {
+		case int:
+			value = float64(v)
+		case float64:
+			value = v
+		}
+		item.Parameters = append(item.Parameters, smhi.Parameter{
+			Name:   params[i].(string),
+			Values: []float64{value},
+		})
+	}
+	return item
+}
+
 func TestParseForecast(t *testing.T) {
 	buf, err := os.ReadFile("testdata/data.json")
 	require.Nil(t, err)
@@ -26,3 +59,1164 @@ func TestParseForecast(t *testing.T) {
 	require.Equal(t, "Moderate rain", symbol.Meaning)
 	require.Equal(t, "🌧 ", symbol.FixedWidth())
 }
+
+func TestTemperatureK(t *testing.T) {
+	item := readForecast(t).TimeSeries[10]
+	require.InDelta(t, 291.75, item.TemperatureK(), 1e-9)
+}
+
+func TestPrecipitationBand(t *testing.T) {
+	item := readForecast(t).TimeSeries[10]
+	pmin, pmean, pmax := item.PrecipitationBand()
+	require.Equal(t, item.Float64("pmin"), pmin)
+	require.Equal(t, item.Float64("pmean"), pmean)
+	require.Equal(t, 2.6, pmax)
+}
+
+func TestOctas(t *testing.T) {
+	item := readForecast(t).TimeSeries[10]
+	require.Equal(t, smhi.Octas(8), item.TotalCloudCover())
+	require.Equal(t, 100.0, item.TotalCloudCover().Percent())
+	require.Equal(t, "8/8", item.TotalCloudCover().String())
+	require.Equal(t, "overcast", item.CloudCoverText())
+}
+
+func TestEmoji(t *testing.T) {
+	symbol := readForecast(t).TimeSeries[10].WeatherSymbol()
+	require.Equal(t, "🌧", symbol.Emoji())
+}
+
+func TestActivePrecipitationCategory(t *testing.T) {
+	forecast := readForecast(t)
+
+	category, ok := forecast.TimeSeries[10].ActivePrecipitationCategory()
+	require.True(t, ok)
+	require.Equal(t, smhi.PrecipitationRain, category)
+
+	_, ok = forecast.TimeSeries[0].ActivePrecipitationCategory()
+	require.False(t, ok)
+}
+
+func TestIntensityLevel(t *testing.T) {
+	require.Equal(t, smhi.IntensityNone, smhi.WeatherSymbols[1].IntensityLevel())
+	require.Equal(t, smhi.IntensityNone, smhi.WeatherSymbols[11].IntensityLevel())
+	require.Equal(t, smhi.IntensityLight, smhi.WeatherSymbols[15].IntensityLevel())
+	require.Equal(t, smhi.IntensityModerate, smhi.WeatherSymbols[19].IntensityLevel())
+	require.Equal(t, smhi.IntensityHeavy, smhi.WeatherSymbols[27].IntensityLevel())
+	require.Equal(t, "heavy", smhi.IntensityHeavy.String())
+}
+
+func TestAllWeatherSymbols(t *testing.T) {
+	symbols := smhi.AllWeatherSymbols()
+	require.Len(t, symbols, 27)
+	require.Equal(t, 1, symbols[0].Value)
+	require.Equal(t, 27, symbols[26].Value)
+}
+
+func TestParseError(t *testing.T) {
+	_, err := smhi.ParseForecast([]byte(`{"approvedTime":"2024-07-13T07:29:11Z","timeSeries":[{"parameters":[{"name":1}]}]}`))
+	var perr *smhi.ParseError
+	require.ErrorAs(t, err, &perr)
+	require.Contains(t, perr.Field, "parameters")
+	require.Contains(t, perr.Context, `"name":1`)
+
+	_, err = smhi.ParseForecast([]byte(`{"timeSeries":[}`))
+	require.ErrorAs(t, err, &perr)
+	require.EqualValues(t, 16, perr.Offset)
+
+	buf := []byte(`{"approvedTime":"2024-07-13T07:29:11Z","timeSeries":[{"validTime": "2024-07-13 08:00","parameters":[]}]}`)
+	_, err = smhi.ParseForecast(buf)
+	require.ErrorAs(t, err, &perr)
+	var timeErr *time.ParseError
+	require.ErrorAs(t, err, &timeErr)
+	require.Equal(t, "validTime", perr.Field)
+	require.EqualValues(t, bytes.Index(buf, []byte(`"2024-07-13 08:00"`)), perr.Offset)
+	require.Contains(t, perr.Context, "2024-07-13 08:00")
+
+	_, err = smhi.ParseForecast([]byte(`{"approvedTime":"yesterday"}`))
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, "approvedTime", perr.Field)
+}
+
+func TestReliableUntil(t *testing.T) {
+	forecast := readForecast(t)
+	require.Equal(t, time.Date(2024, 7, 18, 7, 29, 11, 0, time.UTC), forecast.ReliableUntil())
+}
+
+func TestCurrent(t *testing.T) {
+	forecast := readForecast(t)
+
+	item, ok := forecast.Current(time.Date(2024, 7, 13, 18, 20, 0, 0, time.UTC))
+	require.True(t, ok)
+	require.Equal(t, forecast.TimeSeries[10].ValidTime, item.ValidTime)
+
+	item, ok = forecast.Current(time.Date(2024, 7, 13, 18, 40, 0, 0, time.UTC))
+	require.True(t, ok)
+	require.Equal(t, forecast.TimeSeries[11].ValidTime, item.ValidTime)
+
+	_, ok = (&smhi.Forecast{}).Current(time.Now())
+	require.False(t, ok)
+}
+
+func TestAround(t *testing.T) {
+	forecast := readForecast(t)
+
+	items := forecast.Around(time.Date(2024, 7, 13, 18, 20, 0, 0, time.UTC), 2)
+	require.Len(t, items, 5)
+	require.Equal(t, forecast.TimeSeries[8].ValidTime, items[0].ValidTime)
+	require.Equal(t, forecast.TimeSeries[12].ValidTime, items[4].ValidTime)
+
+	items = forecast.Around(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), 2)
+	require.Len(t, items, 3)
+	require.Equal(t, forecast.TimeSeries[0].ValidTime, items[0].ValidTime)
+
+	require.Nil(t, (&smhi.Forecast{}).Around(time.Now(), 2))
+}
+
+func TestDegreeHours(t *testing.T) {
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "t", 10),
+			newItem(time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC), "t", 20),
+			newItem(time.Date(2024, 1, 1, 7, 0, 0, 0, time.UTC), "t", 5),
+		},
+	}
+	require.Equal(t, 7.0, forecast.DegreeHours(17, true))
+	require.Equal(t, 18.0, forecast.DegreeHours(17, false))
+}
+
+func TestWeatherSymbolsTable(t *testing.T) {
+	// WeatherSymbol() indexes the table by value.
+	require.Len(t, smhi.WeatherSymbols, 28)
+	for idx, symbol := range smhi.WeatherSymbols {
+		require.Equal(t, idx, symbol.Value)
+	}
+}
+
+func TestDaySummary(t *testing.T) {
+	forecast := readForecast(t)
+	loc, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	summary := forecast.DaySummary(time.Date(2024, 7, 13, 0, 0, 0, 0, loc), loc)
+	require.Equal(t, "Moderate rain, 16 to 21°C, moderate rain in the afternoon and evening, wind up to 7 m/s.", summary)
+
+	require.Equal(t, "", forecast.DaySummary(time.Date(2024, 8, 1, 0, 0, 0, 0, loc), loc))
+
+	symbol, ok := forecast.DaySymbol(time.Date(2024, 7, 13, 0, 0, 0, 0, loc), loc)
+	require.True(t, ok)
+	require.Equal(t, 19, symbol.Value)
+
+	_, ok = forecast.DaySymbol(time.Date(2024, 8, 1, 0, 0, 0, 0, loc), loc)
+	require.False(t, ok)
+}
+
+func TestIsFoggy(t *testing.T) {
+	now := time.Now()
+	require.True(t, newItem(now, "vis", 0.4).IsFoggy())
+	require.False(t, newItem(now, "vis", 12.0).IsFoggy())
+	require.True(t, newItem(now, "vis", 12.0, "Wsymb2", 7).IsFoggy())
+	require.False(t, newItem(now).IsFoggy())
+}
+
+func TestClone(t *testing.T) {
+	forecast := readForecast(t)
+	clone := forecast.Clone()
+	require.Equal(t, forecast, clone)
+
+	clone.TimeSeries[0].Parameters[0].Values[0] = 42
+	clone.Geometry.Coordinates[0][0] = 42
+	require.NotEqual(t, 42.0, forecast.TimeSeries[0].Parameters[0].Values[0])
+	require.NotEqual(t, 42.0, forecast.Geometry.Coordinates[0][0])
+}
+
+func TestWindArrow(t *testing.T) {
+	require.Equal(t, '↓', smhi.WindArrow(0))
+	require.Equal(t, '↓', smhi.WindArrow(350))
+	require.Equal(t, '↙', smhi.WindArrow(45))
+	require.Equal(t, '←', smhi.WindArrow(90))
+	require.Equal(t, '↑', smhi.WindArrow(180))
+	require.Equal(t, '→', smhi.WindArrow(-90))
+	require.Equal(t, '←', newItem(time.Now(), "wd", 69).WindArrow())
+}
+
+func TestComfortIndex(t *testing.T) {
+	now := time.Now()
+	require.Equal(t, 100, newItem(now, "t", 21, "r", 50, "ws", 0).ComfortIndex())
+	require.Equal(t, 69, newItem(now, "t", 18, "r", 70, "ws", 3).ComfortIndex())
+	require.Equal(t, 0, newItem(now, "t", -20, "r", 90, "ws", 15).ComfortIndex())
+}
+
+func TestSetWeatherSymbols(t *testing.T) {
+	item := newItem(time.Now(), "Wsymb2", 1)
+
+	table := smhi.AllWeatherSymbols()
+	table = append([]smhi.WeatherSymbol{{}}, table...)
+	table[1].Meaning = "Klart"
+	smhi.SetWeatherSymbols(table)
+	require.Equal(t, "Klart", item.WeatherSymbol().Meaning)
+
+	smhi.SetWeatherSymbols(nil)
+	require.Equal(t, "Clear sky", item.WeatherSymbol().Meaning)
+}
+
+func TestAverage(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "t", 10, "Wsymb2", 1),
+			newItem(start.Add(1*time.Hour), "t", 20, "Wsymb2", 19, "pcat", 3),
+			newItem(start.Add(4*time.Hour), "t", 0, "Wsymb2", 6),
+		},
+	}
+
+	avg := forecast.Average(start, start.Add(4*time.Hour))
+	require.Equal(t, start, avg.ValidTime)
+	require.Equal(t, 17.5, avg.Temperature())
+	require.Equal(t, 19, avg.WeatherSymbol().Value)
+	require.Equal(t, smhi.PrecipitationRain, avg.PrecipitationCategory())
+
+	avg = forecast.Average(start.Add(4*time.Hour), start.Add(5*time.Hour))
+	require.Equal(t, 0.0, avg.Temperature())
+	require.Equal(t, 6, avg.WeatherSymbol().Value)
+
+	require.Empty(t, forecast.Average(start.Add(time.Hour*10), start.Add(time.Hour*11)).Parameters)
+}
+
+func TestDaily(t *testing.T) {
+	forecast := readForecast(t)
+
+	days := forecast.ByDay(time.UTC)
+	require.Len(t, days, 10)
+	require.Len(t, days[0], 16)
+
+	daily := forecast.Daily(time.UTC)
+	require.Len(t, daily.Days, 10)
+	day := daily.Days[0]
+	require.Equal(t, time.Date(2024, 7, 13, 0, 0, 0, 0, time.UTC), day.Date)
+	require.Equal(t, "Moderate rain", day.Symbol.Meaning)
+	require.Less(t, day.MinTemperature, day.MaxTemperature)
+	require.Greater(t, day.Precipitation, 0.0)
+}
+
+func TestDryDayStreak(t *testing.T) {
+	forecast := readForecast(t)
+
+	require.Equal(t, 0, forecast.DryDayStreakAt(time.Date(2024, 7, 13, 10, 0, 0, 0, time.UTC), time.UTC, 1))
+	require.Equal(t, 1, forecast.DryDayStreakAt(time.Date(2024, 7, 16, 10, 0, 0, 0, time.UTC), time.UTC, 1))
+	require.Equal(t, 2, forecast.DryDayStreakAt(time.Date(2024, 7, 18, 10, 0, 0, 0, time.UTC), time.UTC, 1))
+	require.Equal(t, 0, forecast.DryDayStreakAt(time.Date(2024, 8, 1, 10, 0, 0, 0, time.UTC), time.UTC, 1))
+}
+
+func TestICalendar(t *testing.T) {
+	forecast := readForecast(t)
+
+	buf, err := forecast.ICalendar(time.UTC)
+	require.Nil(t, err)
+
+	cal := string(buf)
+	require.True(t, strings.HasPrefix(cal, "BEGIN:VCALENDAR\r\n"))
+	require.True(t, strings.HasSuffix(cal, "END:VCALENDAR\r\n"))
+	require.Contains(t, cal, "SUMMARY:Rain\\, up to")
+	require.Equal(t, strings.Count(cal, "BEGIN:VEVENT"), strings.Count(cal, "END:VEVENT"))
+
+	// Events at another point get other UIDs.
+	p, ok := forecast.ResolvedPoint()
+	require.True(t, ok)
+	require.Regexp(t, fmt.Sprintf(`UID:\w+-\d+-%.4f_%.4f@smhi\r\n`, p[0], p[1]), cal)
+	forecast.Geometry.Coordinates[0][0] += 0.5
+	other, err := forecast.ICalendar(time.UTC)
+	require.Nil(t, err)
+	for _, uid := range regexp.MustCompile(`UID:.*`).FindAllString(cal, -1) {
+		require.NotContains(t, string(other), uid)
+	}
+
+	_, err = forecast.ICalendar(nil)
+	require.NotNil(t, err)
+}
+
+func TestDay(t *testing.T) {
+	now := time.Date(2024, 7, 13, 10, 0, 0, 0, time.UTC)
+	forecast := readForecast(t)
+
+	items, ok := forecast.DayAt(now, 1, time.UTC)
+	require.True(t, ok)
+	require.Len(t, items, 24)
+	require.Equal(t, time.Date(2024, 7, 14, 0, 0, 0, 0, time.UTC), items[0].ValidTime)
+
+	items, ok = forecast.DayAt(now, 0, time.UTC)
+	require.True(t, ok)
+	require.Equal(t, forecast.TimeSeries[0].ValidTime, items[0].ValidTime)
+
+	_, ok = forecast.DayAt(now, 10, time.UTC)
+	require.False(t, ok)
+}
+
+func TestPressureTrend(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "msl", 1010),
+			newItem(start.Add(2*time.Hour), "msl", 1010.5),
+			newItem(start.Add(4*time.Hour), "msl", 1014),
+			newItem(start.Add(6*time.Hour), "msl", 1008),
+		},
+	}
+
+	msl, ok := forecast.InterpolateFloat64("msl", start.Add(3*time.Hour))
+	require.True(t, ok)
+	require.Equal(t, 1012.25, msl)
+
+	require.Equal(t, smhi.PressureSteady, forecast.PressureTrend(start, 2*time.Hour))
+	require.Equal(t, smhi.PressureRising, forecast.PressureTrend(start, 3*time.Hour))
+	require.Equal(t, smhi.PressureFalling, forecast.PressureTrend(start.Add(4*time.Hour), 2*time.Hour))
+	require.Equal(t, smhi.PressureUnknown, forecast.PressureTrend(start, 7*time.Hour))
+	require.Equal(t, "rising", smhi.PressureRising.String())
+}
+
+func TestCloudCoverText(t *testing.T) {
+	require.Equal(t, "clear", smhi.CloudCoverText(0))
+	require.Equal(t, "mostly clear", smhi.CloudCoverText(2))
+	require.Equal(t, "partly cloudy", smhi.CloudCoverText(4))
+	require.Equal(t, "mostly cloudy", smhi.CloudCoverText(7))
+	require.Equal(t, "unknown", smhi.CloudCoverText(9))
+}
+
+func TestThunderWindows(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "tstm", 10),
+			newItem(start.Add(1*time.Hour), "tstm", 40),
+			newItem(start.Add(2*time.Hour), "tstm", 60),
+			newItem(start.Add(3*time.Hour), "tstm", 0),
+			newItem(start.Add(4*time.Hour), "tstm", 30),
+			newItem(start.Add(5*time.Hour), "tstm", 0),
+		},
+	}
+
+	windows := forecast.ThunderWindows(30, time.UTC)
+	require.Equal(t, []smhi.TimeWindow{
+		{Start: start.Add(1 * time.Hour), End: start.Add(3 * time.Hour)},
+		{Start: start.Add(4 * time.Hour), End: start.Add(5 * time.Hour)},
+	}, windows)
+	require.Equal(t, 2*time.Hour, windows[0].Duration())
+}
+
+func TestWindowsMatching(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "Wsymb2", 1),
+			newItem(start.Add(1*time.Hour), "Wsymb2", 2),
+			newItem(start.Add(2*time.Hour), "Wsymb2", 3),
+			newItem(start.Add(3*time.Hour), "Wsymb2", 18),
+			newItem(start.Add(4*time.Hour), "Wsymb2", 1),
+			newItem(start.Add(5*time.Hour), "Wsymb2", 6),
+		},
+	}
+
+	sunny := map[int]bool{1: true, 2: true, 3: true, 4: true}
+	require.Equal(t, []smhi.TimeWindow{
+		{Start: start, End: start.Add(3 * time.Hour)},
+		{Start: start.Add(4 * time.Hour), End: start.Add(5 * time.Hour)},
+	}, forecast.WindowsMatching(sunny, time.Hour))
+	require.Equal(t, []smhi.TimeWindow{
+		{Start: start, End: start.Add(3 * time.Hour)},
+	}, forecast.WindowsMatching(sunny, 2*time.Hour))
+}
+
+func TestMarshalBinary(t *testing.T) {
+	forecast := readForecast(t)
+
+	buf, err := forecast.MarshalBinary()
+	require.Nil(t, err)
+
+	var decoded smhi.Forecast
+	require.Nil(t, decoded.UnmarshalBinary(buf))
+	require.Equal(t, len(forecast.TimeSeries), len(decoded.TimeSeries))
+	require.True(t, forecast.ApprovedTime.Equal(decoded.ApprovedTime))
+	require.Equal(t, forecast.TimeSeries[10].Parameters, decoded.TimeSeries[10].Parameters)
+
+	require.NotNil(t, decoded.UnmarshalBinary([]byte{42}))
+}
+
+func TestValidate(t *testing.T) {
+	forecast := readForecast(t)
+	require.Nil(t, forecast.Validate())
+
+	forecast.TimeSeries[3] = newItem(forecast.TimeSeries[3].ValidTime, "r", 101, "tcc_mean", 4.5, "Wsymb2", 0, "spp", -9, "t", -3.2)
+	err := forecast.Validate()
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "r is 101, expected Integer, 0-100")
+	require.Contains(t, err.Error(), "tcc_mean is 4.5")
+	require.Contains(t, err.Error(), "Wsymb2 is 0")
+	require.NotContains(t, err.Error(), "spp")
+	require.Equal(t, 3, strings.Count(err.Error(), "\n")+1)
+}
+
+func TestPrecipTypeChanges(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "pcat", 0),
+			newItem(start.Add(1*time.Hour), "pcat", 3),
+			newItem(start.Add(2*time.Hour), "pcat", 2),
+			newItem(start.Add(3*time.Hour), "pcat", 0),
+			newItem(start.Add(4*time.Hour), "pcat", 1),
+			newItem(start.Add(5*time.Hour), "pcat", 1),
+		},
+	}
+
+	require.Equal(t, []smhi.PrecipTypeChange{
+		{Time: start.Add(2 * time.Hour), From: smhi.PrecipitationRain, To: smhi.PrecipitationSnowAndRain},
+		{Time: start.Add(4 * time.Hour), From: smhi.PrecipitationSnowAndRain, To: smhi.PrecipitationSnow},
+	}, forecast.PrecipTypeChanges(time.UTC))
+}
+
+func TestTemperatureExtremes(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(now.Add(-time.Hour), "t", -10),
+			newItem(now.Add(time.Hour), "t", 5),
+			newItem(now.Add(2*time.Hour), "t", 2),
+			newItem(now.Add(3*time.Hour), "t", 8),
+			newItem(now.Add(30*time.Hour), "t", 20),
+		},
+	}
+
+	coldest, warmest, ok := forecast.TemperatureExtremesAt(now, 24*time.Hour)
+	require.True(t, ok)
+	require.Equal(t, 2.0, coldest.Temperature())
+	require.Equal(t, 8.0, warmest.Temperature())
+
+	_, _, ok = forecast.TemperatureExtremesAt(now, 30*time.Minute)
+	require.False(t, ok)
+}
+
+func TestExpectedPrecipitation(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "pmin", 0, "pmean", 1, "pmax", 2),
+			newItem(start.Add(1*time.Hour), "pmin", 0.5, "pmean", 2, "pmax", 4),
+			newItem(start.Add(7*time.Hour), "pmin", 9, "pmean", 9, "pmax", 9),
+		},
+	}
+
+	end := start.Add(4 * time.Hour)
+	require.Equal(t, 7.0, forecast.ExpectedPrecipitation(start, end))
+	require.Equal(t, 1.5, forecast.MinPrecipitationTotal(start, end))
+	require.Equal(t, 14.0, forecast.MaxPrecipitationTotal(start, end))
+	require.Equal(t, 13.0, forecast.ExpectedPrecipitation(start, start.Add(24*time.Hour)))
+}
+
+func TestNextSymbol(t *testing.T) {
+	forecast := readForecast(t)
+
+	item, ok := forecast.NextSymbol(19, forecast.ReferenceTime)
+	require.True(t, ok)
+	require.Equal(t, forecast.TimeSeries[8].ValidTime, item.ValidTime)
+
+	item, ok = forecast.NextSymbol(19, item.ValidTime)
+	require.True(t, ok)
+	require.Equal(t, 19, item.WeatherSymbol().Value)
+
+	_, ok = forecast.NextSymbol(27, forecast.ReferenceTime)
+	require.False(t, ok)
+}
+
+func TestSunTimes(t *testing.T) {
+	// Stockholm, sunrise 03:54 and sunset 21:53 local time (CEST).
+	sunrise, sunset, ok := smhi.SunTimes(time.Date(2024, 7, 13, 0, 0, 0, 0, time.UTC), 18.040468, 59.340379)
+	require.True(t, ok)
+	require.WithinDuration(t, time.Date(2024, 7, 13, 1, 54, 0, 0, time.UTC), sunrise, 2*time.Minute)
+	require.WithinDuration(t, time.Date(2024, 7, 13, 19, 53, 0, 0, time.UTC), sunset, 2*time.Minute)
+
+	// Kiruna has midnight sun.
+	_, _, ok = smhi.SunTimes(time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC), 20.2253, 67.8558)
+	require.False(t, ok)
+}
+
+func TestGoldenHours(t *testing.T) {
+	forecast := readForecast(t)
+
+	windows := forecast.GoldenHours(18.040468, 59.340379, 8)
+	require.NotEmpty(t, windows)
+	for _, w := range windows {
+		require.Equal(t, time.Hour, w.Duration())
+	}
+	require.Less(t, len(forecast.GoldenHours(18.040468, 59.340379, 2)), len(windows))
+
+	// The forecast starts after sunrise so the first window ends at the
+	// sunset in Stockholm. With longitude and latitude swapped it would end
+	// hours earlier.
+	require.WithinDuration(t, time.Date(2024, 7, 13, 19, 53, 0, 0, time.UTC), windows[0].End, 2*time.Minute)
+	require.NotEqual(t, windows, forecast.GoldenHours(59.340379, 18.040468, 8))
+}
+
+func TestRainClass(t *testing.T) {
+	now := time.Now()
+	require.Equal(t, smhi.RainNone, newItem(now, "pmean", 0, "pmax", 0).RainClass())
+	require.Equal(t, smhi.RainLight, newItem(now, "pmean", 0, "pmax", 0.3).RainClass())
+	require.Equal(t, smhi.RainLight, newItem(now, "pmean", 1.2, "pmax", 2).RainClass())
+	require.Equal(t, smhi.RainModerate, newItem(now, "pmean", 2.5, "pmax", 4).RainClass())
+	require.Equal(t, smhi.RainHeavy, newItem(now, "pmean", 9, "pmax", 14).RainClass())
+}
+
+func TestDistance(t *testing.T) {
+	// Stockholm to Göteborg is about 398 km.
+	require.InDelta(t, 398000, smhi.Distance(18.0686, 59.3293, 11.9746, 57.7089), 2000)
+	require.Equal(t, 0.0, smhi.Distance(18, 59, 18, 59))
+}
+
+func TestMeanWindDirection(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "wd", 350, "ws", 5),
+			newItem(start.Add(1*time.Hour), "wd", 10, "ws", 5),
+			newItem(start.Add(2*time.Hour), "wd", 90, "ws", 0),
+		},
+	}
+
+	wd, ok := forecast.MeanWindDirection(start, start.Add(2*time.Hour))
+	require.True(t, ok)
+	require.Equal(t, 0, wd)
+
+	_, ok = forecast.MeanWindDirection(start.Add(2*time.Hour), start.Add(3*time.Hour))
+	require.False(t, ok)
+}
+
+func TestSymbolTrend(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "Wsymb2", 1),
+			newItem(start.Add(1*time.Hour), "Wsymb2", 6),
+			newItem(start.Add(2*time.Hour), "Wsymb2", 3),
+		},
+	}
+	require.Equal(t, []int{5, -3}, forecast.SymbolTrend())
+	require.Nil(t, (&smhi.Forecast{}).SymbolTrend())
+}
+
+func TestCumulativePrecipitation(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "pmean", 1),
+			newItem(start.Add(1*time.Hour), "pmean", 0.5),
+			newItem(start.Add(3*time.Hour), "pmean", 2),
+		},
+	}
+	require.Equal(t, []smhi.PrecipPoint{
+		{Time: start, Total: 0},
+		{Time: start.Add(1 * time.Hour), Total: 1},
+		{Time: start.Add(3 * time.Hour), Total: 2},
+	}, forecast.CumulativePrecipitation(time.UTC))
+}
+
+func TestReport(t *testing.T) {
+	item := newItem(time.Now(), "t", 18.6, "Wsymb2", 19, "foo", 1)
+	require.Equal(t, "Air temperature (t): 18.6 C\nWeather symbol (Wsymb2): 19 code\nfoo: 1\n", item.Report())
+}
+
+func TestDayParts(t *testing.T) {
+	forecast := readForecast(t)
+
+	parts := forecast.DayParts(time.Date(2024, 7, 13, 0, 0, 0, 0, time.UTC), time.UTC)
+	require.Len(t, parts, 3)
+	require.NotContains(t, parts, smhi.Night)
+	require.Equal(t, 19, parts[smhi.Afternoon].WeatherSymbol().Value)
+	require.Equal(t, "afternoon", smhi.Afternoon.String())
+
+	parts = forecast.DayParts(time.Date(2024, 7, 14, 0, 0, 0, 0, time.UTC), time.UTC)
+	require.Len(t, parts, 4)
+}
+
+func TestParameterConstants(t *testing.T) {
+	for _, name := range []string{
+		smhi.ParamPressure,
+		smhi.ParamTemperature,
+		smhi.ParamVisibility,
+		smhi.ParamWindDirection,
+		smhi.ParamWindSpeed,
+		smhi.ParamHumidity,
+		smhi.ParamThunderProbability,
+		smhi.ParamTotalCloudCover,
+		smhi.ParamLowCloudCover,
+		smhi.ParamMediumCloudCover,
+		smhi.ParamHighCloudCover,
+		smhi.ParamGust,
+		smhi.ParamMinPrecipitation,
+		smhi.ParamMaxPrecipitation,
+		smhi.ParamFrozenPrecipitation,
+		smhi.ParamPrecipitationCategory,
+		smhi.ParamMeanPrecipitation,
+		smhi.ParamMedianPrecipitation,
+		smhi.ParamWeatherSymbol,
+	} {
+		require.Contains(t, smhi.ParameterDescriptions, name)
+		require.Equal(t, name, smhi.ParameterDescriptions[name].Name)
+	}
+	// Plus the old "wsymb2" alias.
+	require.Len(t, smhi.ParameterDescriptions, 20)
+	require.Equal(t, smhi.ParameterDescriptions[smhi.ParamWeatherSymbol], smhi.ParameterDescriptions["wsymb2"])
+}
+
+func TestWillRain(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(now, "pmean", 0),
+			newItem(now.Add(1*time.Hour), "pmean", 0.1),
+			newItem(now.Add(2*time.Hour), "pmean", 1.5),
+			newItem(now.Add(3*time.Hour), "pmean", 0),
+		},
+	}
+
+	rain, at := forecast.WillRainAt(now, 6*time.Hour, 0.2)
+	require.True(t, rain)
+	require.Equal(t, now.Add(2*time.Hour), at)
+
+	rain, _ = forecast.WillRainAt(now, 30*time.Minute, 0.2)
+	require.False(t, rain)
+
+	rain, at = forecast.WillRainAt(now.Add(150*time.Minute), time.Hour, 0.2)
+	require.True(t, rain)
+	require.Equal(t, now.Add(150*time.Minute), at)
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	forecast := readForecast(t)
+
+	var buf strings.Builder
+	require.Nil(t, forecast.WriteNDJSON(&buf))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, len(forecast.TimeSeries))
+
+	var item map[string]any
+	require.Nil(t, json.Unmarshal([]byte(lines[10]), &item))
+	require.Equal(t, "2024-07-13T18:00:00Z", item["validTime"])
+	require.Equal(t, 18.6, item["t"])
+	require.Equal(t, "Moderate rain", item["weatherSymbol"])
+	require.Equal(t, "Rain", item["precipitationCategory"])
+}
+
+func TestSimpleJSON(t *testing.T) {
+	forecast := readForecast(t)
+
+	buf, err := forecast.SimpleJSON()
+	require.Nil(t, err)
+
+	var items []map[string]any
+	require.Nil(t, json.Unmarshal(buf, &items))
+	require.Len(t, items, len(forecast.TimeSeries))
+	require.Equal(t, "2024-07-13T18:00:00Z", items[10]["time"])
+	require.Equal(t, 18.6, items[10]["temperatureC"])
+	require.Equal(t, 19.0, items[10]["symbol"])
+	require.Equal(t, "Moderate rain", items[10]["symbolText"])
+	require.Contains(t, items[10], "windMps")
+	require.Contains(t, items[10], "windDir")
+	require.Contains(t, items[10], "precipMmH")
+}
+
+func TestRows(t *testing.T) {
+	forecast := readForecast(t)
+
+	header, rows := forecast.Rows([]string{smhi.ParamTemperature, smhi.ParamHumidity, smhi.ParamWeatherSymbol, "nope"}, time.UTC)
+	require.Equal(t, []string{"Time", "t", "r", "Wsymb2", "nope"}, header)
+	require.Len(t, rows, len(forecast.TimeSeries))
+	require.Equal(t, "2024-07-13 18:00", rows[10][0])
+	require.Equal(t, "18.6 C", rows[10][1])
+	require.Regexp(t, `^\d+%$`, rows[10][2])
+	require.Equal(t, "19", rows[10][3])
+	require.Equal(t, "", rows[10][4])
+}
+
+func TestPrecision(t *testing.T) {
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "t", 1.0/3, "ws", 2.25),
+		},
+	}
+
+	buf, err := forecast.SimpleJSON()
+	require.Nil(t, err)
+	require.Contains(t, string(buf), `"temperatureC":0.3,`)
+
+	buf, err = forecast.SimpleJSONPrecision(-1)
+	require.Nil(t, err)
+	require.Contains(t, string(buf), `"temperatureC":0.3333333333333333,`)
+
+	var out strings.Builder
+	require.Nil(t, forecast.WriteNDJSONPrecision(&out, 2))
+	require.Contains(t, out.String(), `"t":0.33,`)
+	require.Contains(t, out.String(), `"ws":2.25}`)
+
+	ws := 0.1
+	forecast.TimeSeries[0].Parameters[1].Values[0] = ws + 0.2
+	_, rows := forecast.Rows([]string{smhi.ParamTemperature, smhi.ParamWindSpeed}, time.UTC)
+	require.Equal(t, []string{"2024-01-01 00:00", "0.3 C", "0.3 m/s"}, rows[0])
+
+	_, rows = forecast.RowsPrecision([]string{smhi.ParamWindSpeed}, time.UTC, -1)
+	require.Equal(t, "0.30000000000000004 m/s", rows[0][1])
+}
+
+func TestSameGridCell(t *testing.T) {
+	require.True(t, smhi.SameGridCell(18.0404, 59.3403, 18.0410, 59.3410))
+	require.False(t, smhi.SameGridCell(18.0404, 59.3403, 18.0686, 59.3293))
+}
+
+func TestModalSymbol(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "Wsymb2", 3),
+			newItem(start.Add(1*time.Hour), "Wsymb2", 1),
+			newItem(start.Add(2*time.Hour), "Wsymb2", 1),
+			newItem(start.Add(3*time.Hour), "Wsymb2", 3),
+			newItem(start.Add(4*time.Hour), "Wsymb2", 1),
+		},
+	}
+
+	symbol, ok := forecast.ModalSymbol(start, start.Add(5*time.Hour))
+	require.True(t, ok)
+	require.Equal(t, 1, symbol.Value)
+
+	symbol, ok = forecast.ModalSymbol(start, start.Add(4*time.Hour))
+	require.True(t, ok)
+	require.Equal(t, 3, symbol.Value)
+
+	_, ok = forecast.ModalSymbol(start.Add(10*time.Hour), start.Add(11*time.Hour))
+	require.False(t, ok)
+}
+
+func TestRender(t *testing.T) {
+	symbol := smhi.WeatherSymbols[19]
+	require.Equal(t, "🌧", symbol.Render(smhi.SymbolEmoji))
+	require.Equal(t, "🌧\ufe0e", symbol.Render(smhi.SymbolText))
+	require.Equal(t, "//", symbol.Render(smhi.SymbolASCII))
+	require.Equal(t, "*", smhi.WeatherSymbols[1].Render(smhi.SymbolASCII))
+	require.Equal(t, "?", smhi.WeatherSymbol{}.Render(smhi.SymbolASCII))
+}
+
+func TestFreezingLevel(t *testing.T) {
+	require.Equal(t, 2000.0, newItem(time.Now(), "t", 13).FreezingLevel())
+	require.Equal(t, 0.0, newItem(time.Now(), "t", -2).FreezingLevel())
+}
+
+func TestAsMap(t *testing.T) {
+	item := newItem(time.Now(), "t", 12.5, "Wsymb2", 3)
+	item.Parameters = append(item.Parameters, smhi.Parameter{Name: "empty"})
+	require.Equal(t, map[string]float64{"t": 12.5, "Wsymb2": 3}, item.AsMap())
+}
+
+func TestLocation(t *testing.T) {
+	for _, tc := range []struct {
+		lon, lat float64
+		name     string
+	}{
+		{18.07, 59.33, "Europe/Stockholm"},
+		{11.97, 57.71, "Europe/Stockholm"},
+		{20.23, 67.86, "Europe/Stockholm"},
+		{10.75, 59.91, "Europe/Oslo"},
+		{18.96, 69.65, "Europe/Oslo"},
+		{12.57, 55.68, "Europe/Copenhagen"},
+		{24.94, 60.17, "Europe/Helsinki"},
+		{19.94, 60.10, "Europe/Mariehamn"},
+		{24.75, 59.44, "Europe/Tallinn"},
+	} {
+		forecast := &smhi.Forecast{Geometry: smhi.Geometry{Coordinates: []smhi.Point{{tc.lon, tc.lat}}}}
+		loc, err := forecast.Location()
+		require.Nil(t, err)
+		require.Equal(t, tc.name, loc.String())
+	}
+
+	forecast := &smhi.Forecast{Geometry: smhi.Geometry{Coordinates: []smhi.Point{{-70, 40}}}}
+	_, err := forecast.Location()
+	require.ErrorIs(t, err, smhi.ErrUnknownTimezone)
+
+	_, err = (&smhi.Forecast{}).Location()
+	require.ErrorIs(t, err, smhi.ErrUnknownTimezone)
+}
+
+func TestSymbolAt(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "Wsymb2", 1),
+			newItem(start.Add(6*time.Hour), "Wsymb2", 18),
+		},
+	}
+
+	symbol, ok := forecast.SymbolAt(start.Add(2 * time.Hour))
+	require.True(t, ok)
+	require.Equal(t, 1, symbol.Value)
+
+	symbol, ok = forecast.SymbolAt(start.Add(4 * time.Hour))
+	require.True(t, ok)
+	require.Equal(t, 18, symbol.Value)
+
+	_, ok = forecast.SymbolAt(start.Add(7 * time.Hour))
+	require.False(t, ok)
+}
+
+func TestUmbrellaScore(t *testing.T) {
+	now := time.Now()
+	require.Equal(t, 0, newItem(now, "pmean", 0, "pmax", 0, "pcat", 0).UmbrellaScore())
+	require.Equal(t, 1, newItem(now, "pmean", 0, "pmax", 0.6, "pcat", 0).UmbrellaScore())
+	require.Equal(t, 8, newItem(now, "pmean", 2.5, "pmax", 4.0, "pcat", 3).UmbrellaScore())
+	require.Equal(t, 4, newItem(now, "pmean", 2.5, "pmax", 4.0, "pcat", 1).UmbrellaScore())
+	require.Equal(t, 10, newItem(now, "pmean", 12.0, "pmax", 20.0, "pcat", 3).UmbrellaScore())
+}
+
+func TestSnowAccumulation(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "pmean", 1.0, "spp", 100),
+			newItem(start.Add(2*time.Hour), "pmean", 2.0, "spp", 50),
+			newItem(start.Add(3*time.Hour), "pmean", 1.0, "spp", -9),
+			newItem(start.Add(4*time.Hour), "pmean", 0, "spp", -9),
+		},
+	}
+
+	require.InDelta(t, 3.0, forecast.SnowAccumulation(start, start.Add(4*time.Hour)), 1e-9)
+	require.InDelta(t, 1.0, forecast.SnowAccumulation(start.Add(time.Hour), start.Add(2*time.Hour)), 1e-9)
+}
+
+func TestDailyMeanTemperature(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "t", 0),
+			newItem(start.Add(1*time.Hour), "t", 10),
+			newItem(start.Add(12*time.Hour), "t", 4),
+			newItem(start.Add(36*time.Hour), "t", 2),
+		},
+	}
+
+	means := forecast.DailyMeanTemperature(time.UTC)
+	require.Len(t, means, 2)
+	require.Equal(t, start, means[0].Date)
+	// 1 hour of 0°C, 11 hours of 10°C and 12 hours of 4°C.
+	require.InDelta(t, 158.0/24, means[0].Temperature, 1e-9)
+	// 12 hours of 4°C, the last item has no duration.
+	require.InDelta(t, 4.0, means[1].Temperature, 1e-9)
+}
+
+func TestSuitable(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "t", 12, "ws", 3.0, "pmean", 0, "pmax", 0),
+			newItem(start.Add(1*time.Hour), "t", 14, "ws", 4.0, "pmean", 0, "pmax", 0),
+			newItem(start.Add(2*time.Hour), "t", 14, "ws", 9.0, "pmean", 0, "pmax", 0),
+			newItem(start.Add(3*time.Hour), "t", 13, "ws", 4.0, "pmean", 0.5, "pmax", 1.0),
+			newItem(start.Add(4*time.Hour), "t", 13, "ws", 4.0, "pmean", 0, "pmax", 0),
+		},
+	}
+
+	require.True(t, forecast.TimeSeries[0].Suitable(smhi.ActivityCycling))
+	require.False(t, forecast.TimeSeries[0].Suitable(smhi.ActivityPicnic))
+	require.False(t, forecast.TimeSeries[3].Suitable(smhi.ActivityCycling))
+	require.True(t, forecast.TimeSeries[3].Suitable(smhi.ActivityRunning))
+
+	require.Equal(t, []smhi.TimeWindow{
+		{Start: start, End: start.Add(2 * time.Hour)},
+	}, forecast.SuitableWindows(smhi.ActivityCycling, time.Hour))
+	require.Equal(t, []smhi.TimeWindow{
+		{Start: start, End: start.Add(4 * time.Hour)},
+	}, forecast.SuitableWindows(smhi.ActivityRunning, time.Hour))
+	require.Empty(t, forecast.SuitableWindows(smhi.ActivityCycling, 3*time.Hour))
+}
+
+func TestNextClear(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "Wsymb2", 3),
+			newItem(start.Add(1*time.Hour), "Wsymb2", 18),
+			newItem(start.Add(2*time.Hour), "Wsymb2", 21),
+			newItem(start.Add(3*time.Hour), "Wsymb2", 6),
+			newItem(start.Add(4*time.Hour), "Wsymb2", 19),
+		},
+	}
+
+	clear, ok := forecast.NextClear(start.Add(90 * time.Minute))
+	require.True(t, ok)
+	require.Equal(t, start.Add(3*time.Hour), clear)
+
+	clear, ok = forecast.NextClear(start.Add(30 * time.Minute))
+	require.True(t, ok)
+	require.Equal(t, start.Add(30*time.Minute), clear)
+
+	_, ok = forecast.NextClear(start.Add(4 * time.Hour))
+	require.False(t, ok)
+}
+
+func TestResolvedPoint(t *testing.T) {
+	forecast := readForecast(t)
+
+	p, ok := forecast.ResolvedPoint()
+	require.True(t, ok)
+	require.Equal(t, smhi.Point{18.040468, 59.340379}, p)
+
+	offset, ok := forecast.OffsetFrom(18.0686, 59.3293)
+	require.True(t, ok)
+	require.InDelta(t, 2000, offset, 100)
+}
+
+func TestNoGeometry(t *testing.T) {
+	buf, err := os.ReadFile("testdata/nogeometry.json")
+	require.Nil(t, err)
+	forecast, err := smhi.ParseForecast(buf)
+	require.Nil(t, err)
+	require.Len(t, forecast.TimeSeries, 3)
+
+	_, ok := forecast.ResolvedPoint()
+	require.False(t, ok)
+	_, ok = forecast.OffsetFrom(18.0686, 59.3293)
+	require.False(t, ok)
+	_, err = forecast.Location()
+	require.ErrorIs(t, err, smhi.ErrUnknownTimezone)
+}
+
+func TestGustFactor(t *testing.T) {
+	factor, ok := newItem(time.Now(), "ws", 4.0, "gust", 10.0).GustFactor()
+	require.True(t, ok)
+	require.Equal(t, 2.5, factor)
+
+	_, ok = newItem(time.Now(), "ws", 0, "gust", 1.0).GustFactor()
+	require.False(t, ok)
+	_, ok = newItem(time.Now(), "ws", 4.0).GustFactor()
+	require.False(t, ok)
+}
+
+func TestFeelsLike(t *testing.T) {
+	now := time.Now()
+	require.InDelta(t, -8.9, newItem(now, "t", -2.0, "ws", 8.0).FeelsLike(), 0.1)
+	require.InDelta(t, 32.8, newItem(now, "t", 30.0, "r", 60, "ws", 2.0).FeelsLike(), 0.1)
+	require.Equal(t, 18.0, newItem(now, "t", 18.0, "ws", 5.0).FeelsLike())
+	require.Equal(t, 30.0, newItem(now, "t", 30.0).FeelsLike())
+}
+
+func TestFeelsLikeSeries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "t", 15.0),
+			newItem(start.Add(time.Hour), "ws", 3.0),
+			newItem(start.Add(2*time.Hour), "t", 16.0),
+		},
+	}
+
+	require.Equal(t, []smhi.FeelsLikePoint{
+		{Time: start, Temperature: 15},
+		{Time: start.Add(2 * time.Hour), Temperature: 16},
+	}, forecast.FeelsLikeSeries())
+}
+
+func TestRapidCoolingEvents(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "t", 15),
+			newItem(start.Add(3*time.Hour), "t", 14),
+			newItem(start.Add(6*time.Hour), "t", 8),
+			newItem(start.Add(9*time.Hour), "t", 2),
+			newItem(start.Add(12*time.Hour), "t", 1),
+			newItem(start.Add(18*time.Hour), "t", 10),
+		},
+	}
+
+	require.Equal(t, []smhi.CoolingEvent{
+		{Start: start, End: start.Add(9 * time.Hour), Drop: 13},
+	}, forecast.RapidCoolingEvents(10, 9*time.Hour))
+	require.Empty(t, forecast.RapidCoolingEvents(10, 3*time.Hour))
+	require.Len(t, forecast.RapidCoolingEvents(5, 3*time.Hour), 2)
+}
+
+func TestComfortableHours(t *testing.T) {
+	start := time.Date(2024, 6, 1, 18, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "t", 20, "ws", 3.0),
+			newItem(start.Add(2*time.Hour), "t", 12, "ws", 3.0),
+			newItem(start.Add(12*time.Hour), "t", 18, "ws", 2.0),
+			newItem(start.Add(18*time.Hour), "t", 22, "ws", 2.0),
+			newItem(start.Add(24*time.Hour), "t", 16, "ws", 2.0),
+		},
+	}
+
+	require.Equal(t, map[string]int{
+		"2024-06-01": 2,
+		"2024-06-02": 12,
+	}, forecast.ComfortableHours(time.UTC, smhi.ActivityPicnic))
+}
+
+func TestWindDescription(t *testing.T) {
+	require.Equal(t, 0, smhi.Beaufort(0.2))
+	require.Equal(t, 2, smhi.Beaufort(2.5))
+	require.Equal(t, 12, smhi.Beaufort(40))
+	require.Equal(t, "north", smhi.CompassDirection(350))
+	require.Equal(t, "northwest", smhi.CompassDirection(310))
+
+	require.Equal(t, "light breeze from the northwest", newItem(time.Now(), "ws", 2.5, "wd", 310).WindDescription())
+	require.Equal(t, "gale from the south", newItem(time.Now(), "ws", 19.0, "wd", 185).WindDescription())
+	require.Equal(t, "calm", newItem(time.Now(), "ws", 0.1, "wd", 90).WindDescription())
+}
+
+func TestTimeUntilRain(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "pmean", 0),
+			newItem(start.Add(1*time.Hour), "pmean", 0.05),
+			newItem(start.Add(2*time.Hour), "pmean", 0.8),
+			newItem(start.Add(3*time.Hour), "pmean", 0),
+		},
+	}
+
+	d, ok := forecast.TimeUntilRain(start.Add(15*time.Minute), 0.1)
+	require.True(t, ok)
+	require.Equal(t, 105*time.Minute, d)
+
+	d, ok = forecast.TimeUntilRain(start.Add(150*time.Minute), 0.1)
+	require.True(t, ok)
+	require.Equal(t, time.Duration(0), d)
+
+	_, ok = forecast.TimeUntilRain(start.Add(15*time.Minute), 1)
+	require.False(t, ok)
+	_, ok = forecast.TimeUntilRain(start.Add(4*time.Hour), 0.1)
+	require.False(t, ok)
+}
+
+func TestVerifyForecast(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "t", 2.0),
+			newItem(start.Add(1*time.Hour), "t", 3.0),
+			newItem(start.Add(2*time.Hour), "ws", 3.0),
+		},
+	}
+	obs := &smhi.Observations{
+		Values: []smhi.Observation{
+			{Time: start.In(time.FixedZone("CET", 3600)), Value: 1.5},
+			{Time: start.Add(30 * time.Minute), Value: 9},
+			{Time: start.Add(2 * time.Hour), Value: 4},
+		},
+	}
+
+	require.Equal(t, []smhi.VerificationPoint{
+		{Time: start, Forecast: 2, Observed: 1.5, Error: 0.5},
+	}, smhi.VerifyForecast(forecast, obs, smhi.ParamTemperature))
+}
+
+func TestBestDay(t *testing.T) {
+	day1 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(day1, "t", 21, "r", 50, "ws", 2.0, "pmean", 1.0, "pmax", 2.0),
+			newItem(day2, "t", 20, "r", 55, "ws", 3.0, "pmean", 0, "pmax", 0),
+			newItem(day3, "t", 12, "r", 80, "ws", 8.0, "pmean", 0, "pmax", 0),
+		},
+	}
+
+	date, ok := forecast.BestDay(time.UTC, smhi.DayScore)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC), date)
+
+	_, ok = (&smhi.Forecast{}).BestDay(time.UTC, smhi.DayScore)
+	require.False(t, ok)
+}
+
+func TestParseForecastInto(t *testing.T) {
+	buf, err := os.ReadFile("testdata/data.json")
+	require.Nil(t, err)
+	small, err := os.ReadFile("testdata/nogeometry.json")
+	require.Nil(t, err)
+
+	var forecast smhi.Forecast
+	require.Nil(t, smhi.ParseForecastInto(buf, &forecast))
+	require.Equal(t, *readForecast(t), forecast)
+
+	require.Nil(t, smhi.ParseForecastInto(small, &forecast))
+	expected, err := smhi.ParseForecast(small)
+	require.Nil(t, err)
+	require.Empty(t, forecast.Geometry.Coordinates)
+	require.Equal(t, expected.TimeSeries, forecast.TimeSeries)
+	require.Equal(t, expected.ApprovedTime, forecast.ApprovedTime)
+
+	var perr *smhi.ParseError
+	require.ErrorAs(t, smhi.ParseForecastInto([]byte("{"), &forecast), &perr)
+}
+
+func TestParseForecastIntoMissingFields(t *testing.T) {
+	var forecast smhi.Forecast
+	require.Nil(t, smhi.ParseForecastInto([]byte(`{"timeSeries":[
+		{"validTime":"2024-01-01T00:00:00Z","parameters":[{"name":"t","levelType":"hl","level":2,"unit":"Cel","values":[1]}]},
+		{"validTime":"2024-01-01T01:00:00Z","parameters":[{"name":"t","levelType":"hl","level":2,"unit":"Cel","values":[2]}]}
+	]}`), &forecast))
+
+	buf := []byte(`{"timeSeries":[
+		{"validTime":"2024-01-02T00:00:00Z"},
+		{"parameters":[{"name":"ws","values":[3]}]}
+	]}`)
+	require.Nil(t, smhi.ParseForecastInto(buf, &forecast))
+	expected, err := smhi.ParseForecast(buf)
+	require.Nil(t, err)
+
+	require.Len(t, forecast.TimeSeries, 2)
+	require.Equal(t, expected.TimeSeries[0].ValidTime, forecast.TimeSeries[0].ValidTime)
+	require.Empty(t, forecast.TimeSeries[0].Parameters)
+	require.Equal(t, expected.TimeSeries[1], forecast.TimeSeries[1])
+	_, ok := forecast.TimeSeries[0].Lookup(smhi.ParamTemperature)
+	require.False(t, ok)
+}
+
+func BenchmarkParseForecast(b *testing.B) {
+	buf, err := os.ReadFile("testdata/data.json")
+	require.Nil(b, err)
+	b.ReportAllocs()
+	for range b.N {
+		if _, err := smhi.ParseForecast(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseForecastInto(b *testing.B) {
+	buf, err := os.ReadFile("testdata/data.json")
+	require.Nil(b, err)
+	var forecast smhi.Forecast
+	b.ReportAllocs()
+	for range b.N {
+		if err := smhi.ParseForecastInto(buf, &forecast); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDiurnalRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast := &smhi.Forecast{
+		TimeSeries: []smhi.TimeSeriesItem{
+			newItem(start, "t", -3.0),
+			newItem(start.Add(12*time.Hour), "t", 4.5),
+			newItem(start.Add(24*time.Hour), "t", 1.0),
+		},
+	}
+
+	require.Equal(t, []smhi.DayRange{
+		{Date: start, Range: 7.5},
+		{Date: start.Add(24 * time.Hour), Range: 0},
+	}, forecast.DiurnalRange(time.UTC))
+}
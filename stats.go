@@ -0,0 +1,270 @@
+package smhi
+
+import (
+	"slices"
+	"time"
+)
+
+// stepDuration returns how long the timeseries item at idx is in effect, i.e.
+// the time until the next item. The last item has zero duration since the
+// forecast says nothing about what comes after it.
+func (f *Forecast) stepDuration(idx int) time.Duration {
+	if idx+1 >= len(f.TimeSeries) {
+		return 0
+	}
+	return f.TimeSeries[idx+1].ValidTime.Sub(f.TimeSeries[idx].ValidTime)
+}
+
+// DegreeHours returns the heating (heating=true) or cooling degree-hours
+// relative to the base temperature in °C. Heating degree-hours accumulate
+// max(0, base - t) and cooling degree-hours max(0, t - base). A common base
+// temperature for heating is 17°C in Sweden.
+//
+// Each item is weighted by the time until the next item, since SMHI's step
+// length grows from 1 hour to 12 hours across the forecast. The last item
+// doesn't contribute.
+func (f *Forecast) DegreeHours(base float64, heating bool) float64 {
+	var sum float64
+	for idx, item := range f.TimeSeries {
+		diff := item.Temperature() - base
+		if heating {
+			diff = -diff
+		}
+		if diff > 0 {
+			sum += diff * f.stepDuration(idx).Hours()
+		}
+	}
+	return sum
+}
+
+// overlap returns how much of the step of the item at idx (see stepDuration)
+// is within the window from start to end.
+func (f *Forecast) overlap(idx int, start, end time.Time) time.Duration {
+	from := f.TimeSeries[idx].ValidTime
+	to := from.Add(f.stepDuration(idx))
+	if from.Before(start) {
+		from = start
+	}
+	if to.After(end) {
+		to = end
+	}
+	if to.After(from) {
+		return to.Sub(from)
+	}
+	return 0
+}
+
+// accumulate integrates the intensity parameter by the given name, e.g.
+// mm/h, over the window from start to end.
+func (f *Forecast) accumulate(name string, start, end time.Time) float64 {
+	var sum float64
+	for idx, item := range f.TimeSeries {
+		sum += item.Float64(name) * f.overlap(idx, start, end).Hours()
+	}
+	return sum
+}
+
+// ExpectedPrecipitation returns the expected precipitation in mm from start to
+// end, i.e. the mean precipitation intensity integrated over the window.
+// Each item's intensity applies until the next item.
+func (f *Forecast) ExpectedPrecipitation(start, end time.Time) float64 {
+	return f.accumulate(ParamMeanPrecipitation, start, end)
+}
+
+// MinPrecipitationTotal is like ExpectedPrecipitation but integrates the
+// minimum precipitation intensity, giving an optimistic total in mm.
+func (f *Forecast) MinPrecipitationTotal(start, end time.Time) float64 {
+	return f.accumulate(ParamMinPrecipitation, start, end)
+}
+
+// MaxPrecipitationTotal is like ExpectedPrecipitation but integrates the
+// maximum precipitation intensity, giving a pessimistic total in mm. Not to
+// be confused with TimeSeriesItem.MaxPrecipitation, which is an intensity.
+func (f *Forecast) MaxPrecipitationTotal(start, end time.Time) float64 {
+	return f.accumulate(ParamMaxPrecipitation, start, end)
+}
+
+// windowWeights returns the items relevant to the window from start to end
+// and their weights in hours. Each item is weighted by how much of its step
+// (see stepDuration) overlaps the window. If no step overlaps, e.g. when the
+// window only contains the last item, the items valid within the window are
+// weighted equally.
+func (f *Forecast) windowWeights(start, end time.Time) ([]TimeSeriesItem, []float64) {
+	var items []TimeSeriesItem
+	var weights []float64
+	for idx, item := range f.TimeSeries {
+		if hours := f.overlap(idx, start, end).Hours(); hours > 0 {
+			items = append(items, item)
+			weights = append(weights, hours)
+		}
+	}
+	if len(items) > 0 {
+		return items, weights
+	}
+
+	for _, item := range f.TimeSeries {
+		if !item.ValidTime.Before(start) && item.ValidTime.Before(end) {
+			items = append(items, item)
+			weights = append(weights, 1)
+		}
+	}
+	return items, weights
+}
+
+// categoricalParameters are parameters that can't be averaged.
+var categoricalParameters = map[string]bool{
+	ParamWeatherSymbol:         true,
+	ParamPrecipitationCategory: true,
+	ParamFrozenPrecipitation:   true,
+	ParamWindDirection:         true,
+}
+
+// Average returns a synthetic timeseries item representing the conditions
+// from start to end. Numeric parameters are the means weighted by how long
+// each item is in effect within the window. The weather symbol is the most
+// severe one in the window (see WeatherSymbol.Severity) and the categorical
+// parameters pcat, spp and wd are taken from the same item as the weather
+// symbol. The returned item is valid at start and has no parameters if the
+// forecast doesn't cover the window.
+func (f *Forecast) Average(start, end time.Time) TimeSeriesItem {
+	avg := TimeSeriesItem{ValidTime: start}
+
+	items, weights := f.windowWeights(start, end)
+	if len(items) == 0 {
+		return avg
+	}
+
+	representative := items[0]
+	for _, item := range items[1:] {
+		if item.WeatherSymbol().Severity() > representative.WeatherSymbol().Severity() {
+			representative = item
+		}
+	}
+
+	sums := make(map[string]float64)
+	totals := make(map[string]float64)
+	for idx, item := range items {
+		for _, p := range item.Parameters {
+			if categoricalParameters[p.Name] || len(p.Values) == 0 {
+				continue
+			}
+			if _, ok := totals[p.Name]; !ok {
+				avg.Parameters = append(avg.Parameters, Parameter{
+					Name:      p.Name,
+					LevelType: p.LevelType,
+					Level:     p.Level,
+					Unit:      p.Unit,
+				})
+			}
+			sums[p.Name] += weights[idx] * p.Values[0]
+			totals[p.Name] += weights[idx]
+		}
+	}
+
+	for idx, p := range avg.Parameters {
+		avg.Parameters[idx].Values = []float64{sums[p.Name] / totals[p.Name]}
+	}
+
+	for _, p := range representative.Parameters {
+		if categoricalParameters[p.Name] {
+			p.Values = slices.Clone(p.Values)
+			avg.Parameters = append(avg.Parameters, p)
+		}
+	}
+
+	return avg
+}
+
+// InterpolateFloat64 returns the parameter by the given name at time t,
+// linearly interpolated between the surrounding timeseries items. Returns
+// false if t is outside of the forecast.
+func (f *Forecast) InterpolateFloat64(name string, t time.Time) (float64, bool) {
+	for idx, item := range f.TimeSeries {
+		if item.ValidTime.Equal(t) {
+			return item.Float64(name), true
+		}
+		if idx == 0 || item.ValidTime.Before(t) {
+			continue
+		}
+		prev := f.TimeSeries[idx-1]
+		if prev.ValidTime.After(t) {
+			break
+		}
+		frac := float64(t.Sub(prev.ValidTime)) / float64(item.ValidTime.Sub(prev.ValidTime))
+		a, b := prev.Float64(name), item.Float64(name)
+		return a + frac*(b-a), true
+	}
+	return 0, false
+}
+
+// SymbolAt returns the weather symbol of the timeseries item nearest to t.
+// Symbols are categorical so they aren't interpolated like
+// InterpolateFloat64. Returns false if t is outside of the forecast.
+func (f *Forecast) SymbolAt(t time.Time) (WeatherSymbol, bool) {
+	n := len(f.TimeSeries)
+	if n == 0 || t.Before(f.TimeSeries[0].ValidTime) || t.After(f.TimeSeries[n-1].ValidTime) {
+		return WeatherSymbol{}, false
+	}
+	return f.TimeSeries[f.nearestIndex(t)].WeatherSymbol(), true
+}
+
+// TemperatureExtremes returns the coldest and warmest timeseries items valid
+// from now until now+horizon. Returns false if no items are within the
+// horizon.
+func (f *Forecast) TemperatureExtremes(horizon time.Duration) (coldest, warmest TimeSeriesItem, ok bool) {
+	return f.TemperatureExtremesAt(time.Now(), horizon)
+}
+
+// TemperatureExtremesAt is like TemperatureExtremes but relative to now
+// instead of the current time.
+func (f *Forecast) TemperatureExtremesAt(now time.Time, horizon time.Duration) (coldest, warmest TimeSeriesItem, ok bool) {
+	end := now.Add(horizon)
+	for _, item := range f.TimeSeries {
+		if item.ValidTime.Before(now) || item.ValidTime.After(end) {
+			continue
+		}
+		if !ok || item.Temperature() < coldest.Temperature() {
+			coldest = item
+		}
+		if !ok || item.Temperature() > warmest.Temperature() {
+			warmest = item
+		}
+		ok = true
+	}
+	return coldest, warmest, ok
+}
+
+// CoolingEvent is a rapid drop in temperature.
+type CoolingEvent struct {
+	Start time.Time
+	End   time.Time
+	// Drop is the temperature decrease in °C from Start to End.
+	Drop float64
+}
+
+// RapidCoolingEvents returns where the temperature falls by at least dropC
+// °C within the duration, e.g. a clear night after a warm day that may give
+// frost. Each event starts at an item and ends at the coldest item within
+// the duration after it. Events don't overlap; scanning resumes at the end
+// of each event.
+func (f *Forecast) RapidCoolingEvents(dropC float64, within time.Duration) []CoolingEvent {
+	var events []CoolingEvent
+	for i := 0; i < len(f.TimeSeries); i++ {
+		start := f.TimeSeries[i]
+		coldest := -1
+		for j := i + 1; j < len(f.TimeSeries) && f.TimeSeries[j].ValidTime.Sub(start.ValidTime) <= within; j++ {
+			if coldest < 0 || f.TimeSeries[j].Temperature() < f.TimeSeries[coldest].Temperature() {
+				coldest = j
+			}
+		}
+		if coldest < 0 {
+			continue
+		}
+		end := f.TimeSeries[coldest]
+		if drop := start.Temperature() - end.Temperature(); drop >= dropC {
+			events = append(events, CoolingEvent{Start: start.ValidTime, End: end.ValidTime, Drop: drop})
+			i = coldest - 1
+		}
+	}
+	return events
+}
@@ -0,0 +1,253 @@
+package smhi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// itemsOn returns the timeseries items whose valid time falls on the same
+// calendar day as date in loc.
+func (f *Forecast) itemsOn(date time.Time, loc *time.Location) []TimeSeriesItem {
+	y, m, d := date.In(loc).Date()
+	var items []TimeSeriesItem
+	for _, item := range f.TimeSeries {
+		iy, im, id := item.ValidTime.In(loc).Date()
+		if iy == y && im == m && id == d {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// mostSevere returns the most severe weather symbol among items.
+func mostSevere(items []TimeSeriesItem) WeatherSymbol {
+	var worst WeatherSymbol
+	for _, item := range items {
+		if symbol := item.WeatherSymbol(); symbol.Severity() > worst.Severity() {
+			worst = symbol
+		}
+	}
+	return worst
+}
+
+// DaySymbol returns the most severe weather symbol during the calendar day of
+// date in loc (see WeatherSymbol.Severity). Returns false if the forecast
+// doesn't cover the day.
+func (f *Forecast) DaySymbol(date time.Time, loc *time.Location) (WeatherSymbol, bool) {
+	items := f.itemsOn(date, loc)
+	if len(items) == 0 {
+		return WeatherSymbol{}, false
+	}
+	return mostSevere(items), true
+}
+
+// NextSymbol returns the first timeseries item after the given time with the
+// weather symbol value, e.g. 20 for heavy rain. Returns false if there is no
+// such item.
+func (f *Forecast) NextSymbol(value int, after time.Time) (TimeSeriesItem, bool) {
+	for _, item := range f.TimeSeries {
+		if item.ValidTime.After(after) && item.WeatherSymbol().Value == value {
+			return item, true
+		}
+	}
+	return TimeSeriesItem{}, false
+}
+
+// NextClear returns when the weather symbol first isn't a precipitation
+// symbol (see WeatherSymbol.IsPrecipitation) at or after the given time. If
+// the item in effect at that time is already dry the time is after. Returns
+// false if the forecast has no dry item.
+func (f *Forecast) NextClear(after time.Time) (time.Time, bool) {
+	for idx, item := range f.TimeSeries {
+		if item.ValidTime.After(after) {
+			if !item.WeatherSymbol().IsPrecipitation() {
+				return item.ValidTime, true
+			}
+			continue
+		}
+		inEffect := idx+1 < len(f.TimeSeries) && f.TimeSeries[idx+1].ValidTime.After(after)
+		if inEffect && !item.WeatherSymbol().IsPrecipitation() {
+			return after, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ModalSymbol returns the most common weather symbol among the timeseries
+// items valid from start (inclusive) to end (exclusive). Ties are broken by
+// picking the most severe symbol. Returns false if there are no items in the
+// window.
+func (f *Forecast) ModalSymbol(start, end time.Time) (WeatherSymbol, bool) {
+	counts := make(map[int]int)
+	var modal WeatherSymbol
+	found := false
+	for _, item := range f.TimeSeries {
+		if item.ValidTime.Before(start) || !item.ValidTime.Before(end) {
+			continue
+		}
+		symbol := item.WeatherSymbol()
+		counts[symbol.Value]++
+		n, best := counts[symbol.Value], counts[modal.Value]
+		if !found || n > best || (n == best && symbol.Severity() > modal.Severity()) {
+			modal = symbol
+			found = true
+		}
+	}
+	return modal, found
+}
+
+// SymbolTrend returns, for each timeseries item except the last, the change
+// in weather symbol severity to the next item (see WeatherSymbol.Severity).
+// Negative values mean the weather improves and positive that it worsens.
+func (f *Forecast) SymbolTrend() []int {
+	if len(f.TimeSeries) < 2 {
+		return nil
+	}
+	trend := make([]int, len(f.TimeSeries)-1)
+	for idx := range trend {
+		trend[idx] = f.TimeSeries[idx+1].WeatherSymbol().Severity() - f.TimeSeries[idx].WeatherSymbol().Severity()
+	}
+	return trend
+}
+
+// DayPart is a part of the day.
+type DayPart int
+
+// Parts of the day.
+const (
+	// Night is 00-06.
+	Night DayPart = iota
+	// Morning is 06-12.
+	Morning
+	// Afternoon is 12-18.
+	Afternoon
+	// Evening is 18-24.
+	Evening
+)
+
+// String returns the part of the day in lower case, e.g. "morning".
+func (p DayPart) String() string {
+	switch p {
+	case Night:
+		return "night"
+	case Morning:
+		return "morning"
+	case Afternoon:
+		return "afternoon"
+	case Evening:
+		return "evening"
+	}
+	return fmt.Sprintf("DayPart(%d)", int(p))
+}
+
+// dayPartOf returns the part of the day for an hour 0-23.
+func dayPartOf(hour int) DayPart {
+	return DayPart(hour / 6)
+}
+
+// DayParts returns a representative timeseries item for each part of the
+// calendar day of date in loc: night 00-06, morning 06-12, afternoon 12-18
+// and evening 18-24. Each item is the Average over the part of the day, i.e.
+// time weighted means with the most severe weather symbol. Parts that the
+// forecast doesn't cover are left out.
+func (f *Forecast) DayParts(date time.Time, loc *time.Location) map[DayPart]TimeSeriesItem {
+	y, m, d := date.In(loc).Date()
+	parts := make(map[DayPart]TimeSeriesItem)
+	for part := Night; part <= Evening; part++ {
+		start := time.Date(y, m, d, int(part)*6, 0, 0, 0, loc)
+		end := time.Date(y, m, d, int(part+1)*6, 0, 0, 0, loc)
+		if avg := f.Average(start, end); len(avg.Parameters) > 0 {
+			parts[part] = avg
+		}
+	}
+	return parts
+}
+
+// DaySummary returns a one sentence summary of the forecast for the calendar
+// day of date in loc, e.g. "Moderate rain, 12 to 18°C, moderate rain in the
+// afternoon, wind up to 9 m/s." The leading symbol is the most severe one
+// during the day (see WeatherSymbol.Severity). Precipitation is described by
+// the category and intensity of the wettest item and the parts of the day
+// with mean precipitation. Returns an empty string if the forecast doesn't
+// cover the day.
+func (f *Forecast) DaySummary(date time.Time, loc *time.Location) string {
+	items := f.itemsOn(date, loc)
+	if len(items) == 0 {
+		return ""
+	}
+
+	low, high := items[0].Temperature(), items[0].Temperature()
+	var wind float64
+	var wettest TimeSeriesItem
+	var parts []DayPart
+	for _, item := range items {
+		low = min(low, item.Temperature())
+		high = max(high, item.Temperature())
+		wind = max(wind, item.WindSpeed())
+		if pmean := item.Float64(ParamMeanPrecipitation); pmean > 0 {
+			if pmean > wettest.Float64(ParamMeanPrecipitation) {
+				wettest = item
+			}
+			part := dayPartOf(item.ValidTime.In(loc).Hour())
+			if len(parts) == 0 || parts[len(parts)-1] != part {
+				parts = append(parts, part)
+			}
+		}
+	}
+
+	phrases := []string{
+		mostSevere(items).Meaning,
+		fmt.Sprintf("%.0f to %.0f°C", low, high),
+	}
+
+	if len(parts) > 0 {
+		precipitation := strings.ToLower(wettest.PrecipitationCategory().String())
+		if intensity := wettest.WeatherSymbol().IntensityLevel(); intensity != IntensityNone {
+			precipitation = intensity.String() + " " + precipitation
+		}
+		names := make([]string, len(parts))
+		for idx, part := range parts {
+			names[idx] = part.String()
+		}
+		phrases = append(phrases, fmt.Sprintf("%s in the %s", precipitation, strings.Join(names, " and ")))
+	}
+
+	phrases = append(phrases, fmt.Sprintf("wind up to %.0f m/s", wind))
+
+	return strings.Join(phrases, ", ") + "."
+}
+
+// DayScore scores a day's timeseries items for outdoor activities. It is the
+// mean ComfortIndex of the items, which favors mild temperatures, moderate
+// humidity and low wind, with items with precipitation (see RainClass)
+// scoring 0. Use it with BestDay.
+func DayScore(items []TimeSeriesItem) float64 {
+	if len(items) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, item := range items {
+		if item.RainClass() == RainNone {
+			sum += float64(item.ComfortIndex())
+		}
+	}
+	return sum / float64(len(items))
+}
+
+// BestDay returns midnight in loc of the calendar day whose timeseries items
+// get the highest score, e.g. from DayScore. Ties go to the earliest day.
+// Returns false if the forecast is empty.
+func (f *Forecast) BestDay(loc *time.Location, score func([]TimeSeriesItem) float64) (date time.Time, ok bool) {
+	var best float64
+	for _, items := range f.ByDay(loc) {
+		s := score(items)
+		if !ok || s > best {
+			y, m, d := items[0].ValidTime.In(loc).Date()
+			date = time.Date(y, m, d, 0, 0, 0, 0, loc)
+			best = s
+			ok = true
+		}
+	}
+	return date, ok
+}
@@ -0,0 +1,86 @@
+package smhi
+
+import (
+	"math"
+	"time"
+)
+
+// julianUnixEpoch is the Julian date of the Unix epoch.
+const julianUnixEpoch = 2440587.5
+
+func toJulian(t time.Time) float64 {
+	return float64(t.Unix())/86400 + julianUnixEpoch
+}
+
+func fromJulian(j float64) time.Time {
+	return time.Unix(0, int64((j-julianUnixEpoch)*86400*1e9)).UTC()
+}
+
+func sinDeg(deg float64) float64 { return math.Sin(deg * math.Pi / 180) }
+func cosDeg(deg float64) float64 { return math.Cos(deg * math.Pi / 180) }
+
+// SunTimes returns the sunrise and sunset in UTC on the UTC calendar day of
+// date for a longitude/latitude coordinate, using the sunrise equation with
+// atmospheric refraction. Returns false if the sun doesn't rise or set that
+// day (midnight sun or polar night). The times are accurate to a minute or
+// so.
+func SunTimes(date time.Time, lon, lat float64) (sunrise, sunset time.Time, ok bool) {
+	y, m, d := date.UTC().Date()
+	n := math.Round(toJulian(time.Date(y, m, d, 12, 0, 0, 0, time.UTC)) - 2451545.0)
+
+	// Mean solar time, solar mean anomaly, equation of the center and
+	// ecliptic longitude.
+	mean := n - lon/360
+	anomaly := math.Mod(357.5291+0.98560028*mean, 360)
+	center := 1.9148*sinDeg(anomaly) + 0.02*sinDeg(2*anomaly) + 0.0003*sinDeg(3*anomaly)
+	ecliptic := math.Mod(anomaly+center+180+102.9372, 360)
+	transit := 2451545.0 + mean + 0.0053*sinDeg(anomaly) - 0.0069*sinDeg(2*ecliptic)
+
+	sinDeclination := sinDeg(ecliptic) * sinDeg(23.4397)
+	cosDeclination := math.Cos(math.Asin(sinDeclination))
+	cosHourAngle := (sinDeg(-0.833) - sinDeg(lat)*sinDeclination) / (cosDeg(lat) * cosDeclination)
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, time.Time{}, false
+	}
+
+	hourAngle := math.Acos(cosHourAngle) * 180 / math.Pi
+	return fromJulian(transit - hourAngle/360), fromJulian(transit + hourAngle/360), true
+}
+
+// GoldenHourDuration is how long after sunrise and before sunset GoldenHours
+// considers golden hour.
+const GoldenHourDuration = time.Hour
+
+// GoldenHours returns the golden hour windows, the hour after sunrise and the
+// hour before sunset, for a longitude/latitude coordinate during which the
+// total cloud cover is at most maxCloudOctas. The cloud cover is interpolated
+// at the middle of each window and windows the forecast doesn't cover are
+// skipped. Days without sunrise or sunset have no golden hours.
+//
+// Note that the coordinate is given as longitude then latitude, like
+// everywhere else in this package, not latitude then longitude.
+func (f *Forecast) GoldenHours(lon, lat float64, maxCloudOctas int) []TimeWindow {
+	if len(f.TimeSeries) == 0 {
+		return nil
+	}
+
+	var windows []TimeWindow
+	first := f.TimeSeries[0].ValidTime.UTC()
+	last := f.TimeSeries[len(f.TimeSeries)-1].ValidTime
+	for day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC); !day.After(last); day = day.AddDate(0, 0, 1) {
+		sunrise, sunset, ok := SunTimes(day, lon, lat)
+		if !ok {
+			continue
+		}
+		for _, w := range []TimeWindow{
+			{Start: sunrise, End: sunrise.Add(GoldenHourDuration)},
+			{Start: sunset.Add(-GoldenHourDuration), End: sunset},
+		} {
+			tcc, ok := f.InterpolateFloat64(ParamTotalCloudCover, w.Start.Add(w.Duration()/2))
+			if ok && tcc <= float64(maxCloudOctas) {
+				windows = append(windows, w)
+			}
+		}
+	}
+	return windows
+}
@@ -0,0 +1,49 @@
+package smhi
+
+// SymbolStyle selects how WeatherSymbol.Render presents a symbol.
+type SymbolStyle int
+
+// Symbol styles.
+const (
+	// SymbolEmoji is the plain Unicode character, see WeatherSymbol.Emoji.
+	SymbolEmoji SymbolStyle = iota
+	// SymbolText is the Unicode character with text presentation, i.e.
+	// without color on terminals that support it.
+	SymbolText
+	// SymbolASCII is an ASCII approximation for dumb terminals.
+	SymbolASCII
+)
+
+// textPresentation is the variation selector requesting text presentation.
+const textPresentation = "\ufe0e"
+
+// asciiSymbols are ASCII approximations of the weather symbols indexed by
+// value. Precipitation is drawn with / for rain, ; for sleet and + for snow,
+// repeated once per intensity level.
+var asciiSymbols = [...]string{
+	"?",
+	"*", "*~", "*~", "*~", "~", "~~", "=",
+	"/", "//", "///", "!",
+	";", ";;", ";;;",
+	"+", "++", "+++",
+	"/", "//", "///", "!",
+	";", ";;", ";;;",
+	"+", "++", "+++",
+}
+
+// Render returns the symbol in the given style.
+func (s WeatherSymbol) Render(style SymbolStyle) string {
+	switch style {
+	case SymbolText:
+		if s.Unicode == "" {
+			return ""
+		}
+		return s.Unicode + textPresentation
+	case SymbolASCII:
+		if s.Value >= 0 && s.Value < len(asciiSymbols) {
+			return asciiSymbols[s.Value]
+		}
+		return "?"
+	}
+	return s.Emoji()
+}
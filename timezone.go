@@ -0,0 +1,117 @@
+package smhi
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownTimezone is returned by Forecast.Location for points outside of
+// the built-in timezone lookup.
+var ErrUnknownTimezone = errors.New("unknown timezone")
+
+// tzBox is a longitude/latitude bounding box in a timezone.
+type tzBox struct {
+	name                           string
+	minLon, maxLon, minLat, maxLat float64
+}
+
+func (b tzBox) contains(lon, lat float64) bool {
+	return lon >= b.minLon && lon < b.maxLon && lat >= b.minLat && lat < b.maxLat
+}
+
+// tzBoxes approximate the countries around Sweden. The first matching box
+// wins so smaller boxes come before the larger ones they overlap.
+var tzBoxes = []tzBox{
+	{"Europe/Mariehamn", 19.3, 21.1, 59.7, 60.6},
+	{"Europe/Helsinki", 21.0, 24.2, 59.7, 65.0},
+	{"Europe/Helsinki", 20.5, 22.0, 68.5, 69.3},
+	{"Europe/Helsinki", 24.2, 31.6, 59.7, 68.5},
+	{"Europe/Helsinki", 25.8, 29.0, 68.5, 70.1},
+	{"Europe/Tallinn", 21.7, 28.3, 57.5, 59.7},
+	{"Europe/Riga", 20.9, 28.3, 55.7, 57.5},
+	{"Europe/Kaliningrad", 19.6, 22.8, 54.3, 55.1},
+	{"Europe/Vilnius", 20.9, 26.9, 53.9, 55.7},
+	{"Europe/Copenhagen", 8.0, 12.65, 54.5, 56.2},
+	{"Europe/Copenhagen", 8.0, 11.0, 56.2, 57.8},
+	{"Europe/Copenhagen", 14.6, 15.3, 54.9, 55.4},
+	{"Europe/Oslo", 4.0, 31.5, 69.06, 71.5},
+}
+
+// outerTZBoxes are checked after Norway and Sweden.
+var outerTZBoxes = []tzBox{
+	{"Europe/Berlin", 6.0, 14.2, 47.3, 54.9},
+	{"Europe/Warsaw", 14.2, 24.1, 49.0, 54.9},
+	{"Europe/Minsk", 23.2, 32.8, 51.3, 56.2},
+	{"Europe/Moscow", 28.0, 41.0, 55.5, 70.0},
+}
+
+// swedishBorder is the approximate longitude of the Norwegian-Swedish border
+// at increasing latitudes. Norway is west of the border.
+var swedishBorder = []Point{
+	{11.0, 57.9},
+	{11.45, 58.9},
+	{11.9, 59.8},
+	{12.5, 61.0},
+	{12.2, 62.0},
+	{12.1, 63.0},
+	{13.9, 64.0},
+	{14.5, 65.0},
+	{15.4, 66.0},
+	{16.1, 67.0},
+	{17.9, 68.0},
+	{18.5, 68.5},
+	{20.6, 69.06},
+}
+
+// borderLon returns the longitude of the Norwegian-Swedish border at lat by
+// linear interpolation.
+func borderLon(lat float64) float64 {
+	for idx := 1; idx < len(swedishBorder); idx++ {
+		a, b := swedishBorder[idx-1], swedishBorder[idx]
+		if lat < b[1] {
+			return a[0] + (lat-a[1])/(b[1]-a[1])*(b[0]-a[0])
+		}
+	}
+	return swedishBorder[len(swedishBorder)-1][0]
+}
+
+// timezoneName returns the IANA timezone name of a longitude/latitude
+// coordinate in the Nordic region.
+func timezoneName(lon, lat float64) (string, bool) {
+	for _, b := range tzBoxes {
+		if b.contains(lon, lat) {
+			return b.name, true
+		}
+	}
+	if lat >= 57.9 && lat < 69.06 && lon >= 4.0 && lon < borderLon(lat) {
+		return "Europe/Oslo", true
+	}
+	if lon >= 10.9 && lon < 24.2 && lat >= 55.2 && lat < 69.06 {
+		return "Europe/Stockholm", true
+	}
+	for _, b := range outerTZBoxes {
+		if b.contains(lon, lat) {
+			return b.name, true
+		}
+	}
+	return "", false
+}
+
+// Location returns the timezone of the forecast point, e.g. to format times
+// in the local time of the point rather than of the caller. The timezone is
+// looked up from a small built-in table covering Sweden and its neighbours.
+// Borders are approximate so points within a few tens of km of a border may
+// get the neighbour's timezone. Returns ErrUnknownTimezone for points outside
+// of the table or if the forecast has no geometry.
+func (f *Forecast) Location() (*time.Location, error) {
+	p, ok := f.ResolvedPoint()
+	if !ok {
+		return nil, fmt.Errorf("%w: forecast has no geometry", ErrUnknownTimezone)
+	}
+	name, ok := timezoneName(p[0], p[1])
+	if !ok {
+		return nil, fmt.Errorf("%w: %f,%f", ErrUnknownTimezone, p[0], p[1])
+	}
+	return time.LoadLocation(name)
+}
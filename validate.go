@@ -0,0 +1,85 @@
+package smhi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// valueRangePattern matches a range like "0-100" or a single value like "-9".
+var valueRangePattern = regexp.MustCompile(`^(-?\d+)(?:-(-?\d+))?$`)
+
+// valueRange is a parsed ParameterDescription.ValueRange.
+type valueRange struct {
+	text    string
+	integer bool
+	// bounds are inclusive min/max pairs. A value must be within one of
+	// them. No bounds means any value.
+	bounds [][2]float64
+}
+
+// parseValueRange parses strings like "Integer, -9 or 0-100".
+func parseValueRange(s string) valueRange {
+	kind, ranges, _ := strings.Cut(s, ",")
+	r := valueRange{text: s, integer: strings.TrimSpace(kind) == "Integer"}
+	for _, part := range strings.Split(ranges, " or ") {
+		m := valueRangePattern.FindStringSubmatch(strings.TrimSpace(part))
+		if m == nil {
+			continue
+		}
+		lo, _ := strconv.ParseFloat(m[1], 64)
+		hi := lo
+		if m[2] != "" {
+			hi, _ = strconv.ParseFloat(m[2], 64)
+		}
+		r.bounds = append(r.bounds, [2]float64{lo, hi})
+	}
+	return r
+}
+
+func (r valueRange) contains(v float64) bool {
+	if r.integer && v != math.Trunc(v) {
+		return false
+	}
+	if len(r.bounds) == 0 {
+		return true
+	}
+	for _, b := range r.bounds {
+		if v >= b[0] && v <= b[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the parameters of each timeseries item are within the
+// ValueRange of their ParameterDescriptions, e.g. that the relative humidity
+// is an integer 0-100. Parameters without a description are not checked. All
+// violations are returned joined in one error.
+func (f *Forecast) Validate() error {
+	ranges := make(map[string]valueRange)
+	var errs []error
+	for _, item := range f.TimeSeries {
+		for _, p := range item.Parameters {
+			r, ok := ranges[p.Name]
+			if !ok {
+				desc, ok := ParameterDescriptions[p.Name]
+				if !ok {
+					continue
+				}
+				r = parseValueRange(desc.ValueRange)
+				ranges[p.Name] = r
+			}
+			for _, v := range p.Values {
+				if !r.contains(v) {
+					errs = append(errs, fmt.Errorf("%s: %s is %v, expected %s", item.ValidTime.Format(time.RFC3339), p.Name, v, r.text))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,56 @@
+package smhi
+
+import "time"
+
+// Observation is an observed value at a point in time.
+type Observation struct {
+	Time  time.Time
+	Value float64
+}
+
+// Observations is a series of observed values of one parameter at a
+// station, e.g. the air temperature from SMHI's meteorological observations
+// API, in the same unit as the corresponding forecast parameter.
+type Observations struct {
+	Values []Observation
+}
+
+// VerificationPoint compares a forecast value to the observed value at the
+// same time.
+type VerificationPoint struct {
+	Time     time.Time
+	Forecast float64
+	Observed float64
+	// Error is Forecast - Observed.
+	Error float64
+}
+
+// VerifyForecast compares the forecast parameter by the given name, e.g.
+// ParamTemperature, to observations of it. Forecast items and observations
+// are matched by exact time, so observations between forecast steps are
+// ignored. Items without the parameter are skipped.
+func VerifyForecast(f *Forecast, obs *Observations, param string) []VerificationPoint {
+	observed := make(map[time.Time]float64, len(obs.Values))
+	for _, o := range obs.Values {
+		observed[o.Time.UTC()] = o.Value
+	}
+
+	var points []VerificationPoint
+	for _, item := range f.TimeSeries {
+		value, ok := item.Lookup(param)
+		if !ok {
+			continue
+		}
+		o, ok := observed[item.ValidTime.UTC()]
+		if !ok {
+			continue
+		}
+		points = append(points, VerificationPoint{
+			Time:     item.ValidTime,
+			Forecast: value,
+			Observed: o,
+			Error:    value - o,
+		})
+	}
+	return points
+}
@@ -0,0 +1,119 @@
+package smhi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DefaultWarningsBaseURL is the base URL of the SMHI impact-based warnings
+// API.
+const DefaultWarningsBaseURL = "https://opendata-download-warnings.smhi.se"
+
+// HydroWarning is a hydrological warning, e.g. for high flows, in one area.
+type HydroWarning struct {
+	ID int
+	// Event is the kind of warning, e.g. "High flow".
+	Event string
+	// Area is the name of the warning area, e.g. a river basin.
+	Area string
+	// Counties are the names of the counties affected by the warning.
+	Counties []string
+	// Severity is the warning level code, e.g. "YELLOW", "ORANGE", "RED"
+	// or "MESSAGE".
+	Severity string
+	Start    time.Time
+	// End is the zero time if SMHI hasn't given an end of the warning.
+	End time.Time
+}
+
+// warningText is a text in SMHI's warnings API, given in Swedish and
+// usually also in English.
+type warningText struct {
+	SV   string
+	EN   string
+	Code string
+}
+
+func (t warningText) String() string {
+	if t.EN != "" {
+		return t.EN
+	}
+	return t.SV
+}
+
+// warning is an event in SMHI's warnings response, with one entry per
+// affected area.
+type warning struct {
+	Event struct {
+		warningText
+		MHOClassification warningText
+	}
+	WarningAreas []struct {
+		ID               int
+		ApproximateStart time.Time
+		ApproximateEnd   time.Time
+		AreaName         warningText
+		WarningLevel     warningText
+		EventDescription warningText
+		AffectedAreas    []warningText
+	}
+}
+
+func (c *Client) warningsURL() string {
+	base := c.WarningsBaseURL
+	if base == "" {
+		base = DefaultWarningsBaseURL
+	}
+	return base + "/ibww/api/version/1/warning.json"
+}
+
+// GetHydroWarnings requests the current warnings and returns the
+// hydrological ones, one per warning area. Area, event and county names are
+// in English when SMHI provides it and in Swedish otherwise.
+func (c *Client) GetHydroWarnings(ctx context.Context) ([]HydroWarning, error) {
+	buf, _, err := c.get(ctx, c.warningsURL())
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []warning
+	if err := json.Unmarshal(buf, &warnings); err != nil {
+		return nil, fmt.Errorf("decode warnings: %w", err)
+	}
+
+	var hydro []HydroWarning
+	for _, w := range warnings {
+		if w.Event.MHOClassification.Code != "HYDROLOGY" {
+			continue
+		}
+		for _, area := range w.WarningAreas {
+			event := area.EventDescription.String()
+			if event == "" {
+				event = w.Event.String()
+			}
+			counties := make([]string, len(area.AffectedAreas))
+			for idx, a := range area.AffectedAreas {
+				counties[idx] = a.String()
+			}
+			hydro = append(hydro, HydroWarning{
+				ID:       area.ID,
+				Event:    event,
+				Area:     area.AreaName.String(),
+				Counties: counties,
+				Severity: area.WarningLevel.Code,
+				Start:    area.ApproximateStart,
+				End:      area.ApproximateEnd,
+			})
+		}
+	}
+	return hydro, nil
+}
+
+// GetHydroWarnings requests the current hydrological warnings. It uses a
+// zero value Client, see Client.GetHydroWarnings.
+func GetHydroWarnings() ([]HydroWarning, error) {
+	var c Client
+	return c.GetHydroWarnings(context.Background())
+}
@@ -0,0 +1,134 @@
+package smhi
+
+import (
+	"math"
+	"time"
+)
+
+// WindDirection returns the wind direction in degrees for this forecast
+// timeseries item. Like all meteorological wind directions it is the
+// direction the wind is blowing from, 0 being north and 90 east.
+func (i TimeSeriesItem) WindDirection() int {
+	return i.Int(ParamWindDirection)
+}
+
+// GustFactor returns the ratio of the gust speed to the mean wind speed, a
+// measure of how gusty or turbulent the wind is. Returns false if either
+// parameter is missing or the wind speed is 0.
+func (i TimeSeriesItem) GustFactor() (float64, bool) {
+	gust, ok := i.Lookup(ParamGust)
+	if !ok {
+		return 0, false
+	}
+	ws, ok := i.Lookup(ParamWindSpeed)
+	if !ok || ws <= 0 {
+		return 0, false
+	}
+	return gust / ws, true
+}
+
+// windArrows are arrows pointing north, northeast etc.
+var windArrows = [...]rune{'↑', '↗', '→', '↘', '↓', '↙', '←', '↖'}
+
+// WindArrow returns an arrow pointing in the direction the wind blows to, for
+// a meteorological wind direction in degrees (the direction it blows from).
+// A northerly wind (0°) thus gives ↓. The direction is rounded to the nearest
+// 45° sector.
+func WindArrow(degrees int) rune {
+	to := ((degrees+180)%360 + 360) % 360
+	return windArrows[((to+22)/45)%8]
+}
+
+// WindArrow returns an arrow pointing in the direction the wind blows to. See
+// WindArrow.
+func (i TimeSeriesItem) WindArrow() rune {
+	return WindArrow(i.WindDirection())
+}
+
+// beaufortLimits are the upper wind speed limits in m/s of Beaufort force 0
+// to 11. Higher speeds are force 12.
+var beaufortLimits = [...]float64{0.3, 1.6, 3.4, 5.5, 8.0, 10.8, 13.9, 17.2, 20.8, 24.5, 28.5, 32.7}
+
+// beaufortDescriptions describe Beaufort force 0 to 12.
+var beaufortDescriptions = [...]string{
+	"calm",
+	"light air",
+	"light breeze",
+	"gentle breeze",
+	"moderate breeze",
+	"fresh breeze",
+	"strong breeze",
+	"near gale",
+	"gale",
+	"strong gale",
+	"storm",
+	"violent storm",
+	"hurricane force",
+}
+
+// Beaufort returns the Beaufort force, 0 to 12, of a wind speed in m/s.
+func Beaufort(speed float64) int {
+	for force, limit := range beaufortLimits {
+		if speed < limit {
+			return force
+		}
+	}
+	return len(beaufortLimits)
+}
+
+// BeaufortDescription returns the description of a Beaufort force, e.g.
+// "light breeze" for 2.
+func BeaufortDescription(force int) string {
+	force = min(max(force, 0), len(beaufortDescriptions)-1)
+	return beaufortDescriptions[force]
+}
+
+// compassPoints are the names of north, northeast etc.
+var compassPoints = [...]string{"north", "northeast", "east", "southeast", "south", "southwest", "west", "northwest"}
+
+// CompassDirection returns the name of the compass point nearest to a
+// direction in degrees, e.g. "northwest" for 310.
+func CompassDirection(degrees int) string {
+	degrees = (degrees%360 + 360) % 360
+	return compassPoints[((degrees+22)/45)%8]
+}
+
+// Beaufort returns the Beaufort force of the wind speed for this forecast
+// timeseries item.
+func (i TimeSeriesItem) Beaufort() int {
+	return Beaufort(i.WindSpeed())
+}
+
+// WindDescription describes the wind for this forecast timeseries item, e.g.
+// "light breeze from the northwest", or "calm" at Beaufort force 0.
+func (i TimeSeriesItem) WindDescription() string {
+	force := i.Beaufort()
+	if force == 0 {
+		return BeaufortDescription(force)
+	}
+	return BeaufortDescription(force) + " from the " + CompassDirection(i.WindDirection())
+}
+
+// MeanWindDirection returns the mean wind direction in degrees from start to
+// end. It is the circular mean of the wind vectors, weighted by wind speed and
+// how long each item is in effect, so 350° and 10° average to 0° rather than
+// 180°. Returns false if the forecast doesn't cover the window or the winds
+// cancel out, e.g. when calm.
+func (f *Forecast) MeanWindDirection(start, end time.Time) (int, bool) {
+	items, weights := f.windowWeights(start, end)
+
+	var x, y float64
+	for idx, item := range items {
+		w := weights[idx] * item.WindSpeed()
+		rad := float64(item.WindDirection()) * math.Pi / 180
+		x += w * math.Sin(rad)
+		y += w * math.Cos(rad)
+	}
+
+	if math.Hypot(x, y) < 1e-9 {
+		return 0, false
+	}
+
+	deg := int(math.Round(math.Atan2(x, y) * 180 / math.Pi))
+	return (deg + 360) % 360, true
+}
@@ -0,0 +1,65 @@
+package smhi
+
+import "time"
+
+// TimeWindow is an interval of time.
+type TimeWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the window.
+func (w TimeWindow) Duration() time.Duration {
+	return w.End.Sub(w.Start)
+}
+
+// runs returns the first and last index of each run of consecutive
+// timeseries items for which match returns true.
+func (f *Forecast) runs(match func(TimeSeriesItem) bool) [][2]int {
+	var runs [][2]int
+	for idx, item := range f.TimeSeries {
+		if !match(item) {
+			continue
+		}
+		if n := len(runs); n > 0 && runs[n-1][1] == idx-1 {
+			runs[n-1][1] = idx
+		} else {
+			runs = append(runs, [2]int{idx, idx})
+		}
+	}
+	return runs
+}
+
+// runWindow returns the window covered by the items first to last. The
+// window ends when the step of the last item ends, see stepDuration.
+func (f *Forecast) runWindow(first, last int) TimeWindow {
+	return TimeWindow{
+		Start: f.TimeSeries[first].ValidTime,
+		End:   f.TimeSeries[last].ValidTime.Add(f.stepDuration(last)),
+	}
+}
+
+// ThunderWindows returns the windows where the thunder probability is at
+// least minProbability percent. Adjacent qualifying items are merged into
+// one window. The times are in loc.
+func (f *Forecast) ThunderWindows(minProbability int, loc *time.Location) []TimeWindow {
+	var windows []TimeWindow
+	for _, run := range f.runs(func(i TimeSeriesItem) bool { return i.ThunderProbability() >= minProbability }) {
+		w := f.runWindow(run[0], run[1])
+		windows = append(windows, TimeWindow{Start: w.Start.In(loc), End: w.End.In(loc)})
+	}
+	return windows
+}
+
+// WindowsMatching returns the windows where the weather symbol code of every
+// item is in symbols and that last at least minDuration, e.g. clear to
+// variable skies (1-4) for beach weather.
+func (f *Forecast) WindowsMatching(symbols map[int]bool, minDuration time.Duration) []TimeWindow {
+	var windows []TimeWindow
+	for _, run := range f.runs(func(i TimeSeriesItem) bool { return symbols[i.Int(ParamWeatherSymbol)] }) {
+		if w := f.runWindow(run[0], run[1]); w.Duration() >= minDuration {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}